@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// currentChannel returns the channel with the given name, or nil when it does not exist.
+func currentChannel(ctx context.Context, client *skyway.ChannelClient, name string) (*skyway.Channel, error) {
+	channel, err := client.FindChannel(ctx, "", name)
+	if errors.Is(err, skyway.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a channel manifest",
+	Long: `Bring channels, their metadata, members and publications in line with a YAML manifest.
+Channels and members are identified by name, and publications by their content type and metadata.
+Each change is printed as "+" (create), "~" (update) or "-" (delete).
+Members whose type or subtype differs from the manifest are replaced, because they cannot be updated.
+The TTL of members is renewed when it differs from the manifest by more than a minute.
+When members share a name, the manifest is applied to the first one, and the others are removed with --prune.
+With --prune, members without a name are kept, because the manifest cannot refer to them.
+
+Example manifest:
+
+  channels:
+    - name: room
+      metadata: '{"topic":"test"}'
+      members:
+        - name: alice
+          metadata: host
+          ttl: 3600
+          publications:
+            - contentType: video
+            - contentType: audio
+              enabled: false`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.app_id", cmd.Flags().Lookup("app-id"))
+		viper.BindPFlag("skyway.secret_key", cmd.Flags().Lookup("secret-key"))
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		filename, err := cmd.Flags().GetString("filename")
+		checkErr(err)
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		checkErr(err)
+
+		prune, err := cmd.Flags().GetBool("prune")
+		checkErr(err)
+
+		manifest, err := internal.LoadManifest(filename)
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		changed := false
+		for _, channelManifest := range manifest.Channels {
+			current, err := currentChannel(cmd.Context(), client, channelManifest.Name)
+			checkErr(err)
+
+			actions := internal.PlanApply(channelManifest, current, prune)
+			for _, action := range actions {
+				changed = true
+				fmt.Println(action)
+				if !dryRun {
+					checkErr(action.Run(cmd.Context(), client))
+				}
+			}
+
+			if current == nil && len(actions) > 0 && !dryRun {
+				created, err := currentChannel(cmd.Context(), client, channelManifest.Name)
+				checkErr(err)
+				if created != nil {
+					recordChannel(appId, *created, "apply")
+				}
+			}
+		}
+
+		if !changed {
+			fmt.Println("No changes")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().String("app-id", "", "SkyWay App ID. This option can also be set by the skyway.app_id configuration or the SKYWAY_APP_ID environment variable.")
+	applyCmd.Flags().String("secret-key", "", "SkyWay Secret Key. This option can also be set by the skyway.secret_key configuration or the SKYWAY_SECRET_KEY environment variable.")
+	applyCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+
+	applyCmd.Flags().StringP("filename", "f", "", "Manifest file")
+	applyCmd.MarkFlagRequired("filename")
+	applyCmd.Flags().Bool("dry-run", false, "Only print the changes without applying them")
+	applyCmd.Flags().Bool("prune", false, "Remove members and publications which are not in the manifest")
+}
@@ -1,12 +1,107 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// printJSON prints v as JSON, indented when pretty is true.
+func printJSON(v interface{}, pretty bool) {
+	if pretty {
+		jsonString, err := json.MarshalIndent(v, "", "  ")
+		checkErr(err)
+
+		fmt.Println(string(jsonString))
+	} else {
+		jsonString, err := json.Marshal(v)
+		checkErr(err)
+
+		fmt.Println(string(jsonString))
+	}
+}
+
+// adminTokenSource provides a SkyWay Admin Auth Token which is renewed a minute before it expires,
+// so that long-running commands never use an expired token.
+func adminTokenSource(appId string, secretKey string) skyway.TokenSource {
+	return skyway.ReuseTokenSource(skyway.TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return GenerateAdminToken(appId, secretKey, 3600, []string{})
+	}), time.Minute)
+}
+
+func newChannelClient(appId string, secretKey string, url string) *skyway.ChannelClient {
+	return skyway.NewChannelClient(url, adminTokenSource(appId, secretKey), skyway.WithRetryPolicy(retryPolicy()))
+}
+
+// findChannel looks up a channel by id or name and returns an error when it does not exist.
+func findChannel(ctx context.Context, client *skyway.ChannelClient, id string, name string) (skyway.Channel, error) {
+	if id == "" && name == "" {
+		return skyway.Channel{}, fmt.Errorf("channel id or name is required")
+	}
+
+	return client.FindChannel(ctx, id, name)
+}
+
+// updateRegistry applies update to the local channel registry.
+// Failures are only logged because the registry is a convenience and should not fail the command.
+func updateRegistry(update func(registry *internal.Registry)) {
+	path, err := internal.RegistryPath()
+	if err != nil {
+		slog.Warn("Failed to locate channel registry", "err", err)
+		return
+	}
+
+	_, err = internal.UpdateRegistry(path, func(registry *internal.Registry) error {
+		update(registry)
+		return nil
+	})
+	if err != nil {
+		slog.Warn("Failed to update channel registry", "file", path, "err", err)
+	}
+}
+
+// recordChannel records the channel in the local channel registry.
+func recordChannel(appId string, channel skyway.Channel, source string) {
+	if channel.Id == "" {
+		return
+	}
+	updateRegistry(func(registry *internal.Registry) {
+		registry.Record(appId, channel, source, time.Now())
+	})
+}
+
+// completeChannelIds completes channel ids from the local channel registry.
+func completeChannelIds(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	path, err := internal.RegistryPath()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	registry, err := internal.LoadRegistry(path)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	appId := viper.GetString("skyway.app_id")
+	var completions []string
+	for _, entry := range registry.Channels {
+		if entry.DeletedAt != nil || (appId != "" && entry.AppId != appId) {
+			continue
+		}
+		if strings.HasPrefix(entry.Id, toComplete) {
+			completions = append(completions, entry.Id+"\t"+entry.Name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
 // channelCmd represents the channel command
 var channelCmd = &cobra.Command{
 	Use:   "channel",
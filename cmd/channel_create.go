@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/kadoshita/skyway-cli/internal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -18,31 +17,32 @@ var channelCreateCmd = &cobra.Command{
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		channelName, err := cmd.Flags().GetString("name")
-		cobra.CheckErr(err)
+		checkErr(err)
 		metadata, err := cmd.Flags().GetString("metadata")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		appId := viper.GetString("skyway.app_id")
 		secretKey := viper.GetString("skyway.secret_key")
 		url := viper.GetString("skyway.channel.url")
 
 		pretty, err := cmd.Flags().GetBool("pretty")
-		cobra.CheckErr(err)
+		checkErr(err)
 
-		token, err := GenerateAdminToken(appId, secretKey, 3600, []string{})
-		cobra.CheckErr(err)
+		client := newChannelClient(appId, secretKey, url)
 
-		channel, err := internal.CreateChannel(channelName, metadata, token, url)
-		cobra.CheckErr(err)
+		channel, err := client.CreateChannel(cmd.Context(), channelName, metadata)
+		checkErr(err)
+
+		recordChannel(appId, channel, "create")
 
 		if pretty {
 			jsonString, err := json.MarshalIndent(channel, "", "  ")
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		} else {
 			jsonString, err := json.Marshal(channel)
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		}
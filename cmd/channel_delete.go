@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Confirm asks the user a yes/no question and reports whether the answer was yes.
+func Confirm(ctx context.Context, in io.Reader, out io.Writer, message string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", message)
+
+	type result struct {
+		answer string
+		err    error
+	}
+	answered := make(chan result, 1)
+	go func() {
+		reader := bufio.NewReader(in)
+		answer, err := reader.ReadString('\n')
+		answered <- result{answer, err}
+	}()
+
+	var answer string
+	select {
+	case <-ctx.Done():
+		fmt.Fprintln(out)
+		return false, ctx.Err()
+	case r := <-answered:
+		if r.err != nil && r.err != io.EOF {
+			return false, r.err
+		}
+		answer = r.answer
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// channelDeleteCmd represents the delete command
+var channelDeleteCmd = &cobra.Command{
+	Use:   "delete [id...]",
+	Short: "Delete channels by id or name",
+	Long: `Delete channels by id or name.
+The deleted channels are printed as JSON.
+Without --yes, a confirmation prompt is shown before deleting.`,
+	ValidArgsFunction: completeChannelIds,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		name, err := cmd.Flags().GetString("name")
+		checkErr(err)
+
+		yes, err := cmd.Flags().GetBool("yes")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		if len(args) == 0 && name == "" {
+			checkErr(fmt.Errorf("channel id or --name is required"))
+		}
+
+		client := newChannelClient(appId, secretKey, url)
+
+		var channels []skyway.Channel
+		for _, id := range args {
+			channel, err := findChannel(cmd.Context(), client, id, "")
+			checkErr(err)
+			channels = append(channels, channel)
+		}
+		if name != "" {
+			channel, err := findChannel(cmd.Context(), client, "", name)
+			checkErr(err)
+			channels = append(channels, channel)
+		}
+
+		if !yes {
+			var targets []string
+			for _, channel := range channels {
+				targets = append(targets, fmt.Sprintf("%s (name: %s)", channel.Id, channel.Name))
+			}
+			ok, err := Confirm(cmd.Context(), cmd.InOrStdin(), cmd.ErrOrStderr(), "Delete channel "+strings.Join(targets, ", ")+"?")
+			checkErr(err)
+			if !ok {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Aborted")
+				return
+			}
+		}
+
+		for _, channel := range channels {
+			err := client.DeleteChannel(cmd.Context(), channel.Id)
+			checkErr(err)
+
+			updateRegistry(func(registry *internal.Registry) {
+				registry.MarkDeleted(appId, channel.Id, time.Now())
+			})
+
+			printJSON(channel, pretty)
+		}
+	},
+}
+
+func init() {
+	channelCmd.AddCommand(channelDeleteCmd)
+
+	channelDeleteCmd.Flags().String("name", "", "Channel name")
+	channelDeleteCmd.Flags().BoolP("yes", "y", false, "Delete without confirmation")
+	channelDeleteCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
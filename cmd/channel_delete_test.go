@@ -0,0 +1,60 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kadoshita/skyway-cli/cmd"
+)
+
+func TestConfirm(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"yと答えた場合は削除する", "y\n", true},
+		{"yesと答えた場合は削除する", "yes\n", true},
+		{"大文字と前後の空白は無視する", "  YES \n", true},
+		{"改行が無くてもyと答えた場合は削除する", "y", true},
+		{"nと答えた場合は削除しない", "n\n", false},
+		{"何も答えなかった場合は削除しない", "\n", false},
+		{"入力が無い場合は削除しない", "", false},
+		{"y以外の文字列の場合は削除しない", "yeah\n", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var out bytes.Buffer
+			ok, err := cmd.Confirm(context.Background(), strings.NewReader(c.input), &out, "Delete channel c1?")
+			if err != nil {
+				t.Fatalf("エラーが発生しない: %v", err)
+			}
+			if ok != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, ok)
+			}
+			if out.String() != "Delete channel c1? [y/N]: " {
+				t.Errorf("unexpected prompt: %q", out.String())
+			}
+		})
+	}
+
+	t.Run("回答を待つ間にキャンセルされた場合はエラー", func(t *testing.T) {
+		in, writer := io.Pipe()
+		defer writer.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var out bytes.Buffer
+		ok, err := cmd.Confirm(ctx, in, &out, "Delete channel c1?")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if ok {
+			t.Error("キャンセルされた場合は削除しない")
+		}
+	})
+}
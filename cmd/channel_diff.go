@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelDiffCmd represents the diff command
+var channelDiffCmd = &cobra.Command{
+	Use:   "diff <snapshot> <snapshot|live>",
+	Short: "Compare two states of a channel",
+	Long: `Compare two states of a channel saved by "channel snapshot".
+When the second argument is "live", the first snapshot is compared with the current state of the channel.
+Added, removed and updated members, publications and subscriptions are printed as
+"+" (added), "-" (removed) or "~" (updated), or as JSON with --output json.
+With --exit-code, the command exits with 1 when there are differences, and 0 when there are none.
+Like git diff, errors exit with 2 instead of 1, while not found, unauthorized, timeout and interrupted keep their exit codes.`,
+	Args: cobra.ExactArgs(2),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		output, err := cmd.Flags().GetString("output")
+		checkErrWithCode(err, exitDiffError)
+		if output != "text" && output != "json" {
+			checkErrWithCode(fmt.Errorf("--output should be text or json. value: %s", output), exitDiffError)
+		}
+
+		exitCode, err := cmd.Flags().GetBool("exit-code")
+		checkErrWithCode(err, exitDiffError)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErrWithCode(err, exitDiffError)
+
+		before, err := loadChannelSnapshot(args[0])
+		checkErrWithCode(err, exitDiffError)
+
+		var after skyway.Channel
+		if args[1] == "live" {
+			client := newChannelClient(appId, secretKey, url)
+
+			after, err = client.FindChannel(cmd.Context(), before.Channel.Id, "")
+			checkErrWithCode(err, exitDiffError)
+		} else {
+			snapshot, err := loadChannelSnapshot(args[1])
+			checkErrWithCode(err, exitDiffError)
+			after = snapshot.Channel
+		}
+
+		changes := internal.DiffChannels(before.Channel, after)
+
+		if output == "json" {
+			if changes == nil {
+				changes = []internal.Change{}
+			}
+			printJSON(changes, pretty)
+		} else {
+			for _, change := range changes {
+				fmt.Println(change)
+			}
+		}
+
+		if exitCode && len(changes) > 0 {
+			os.Exit(exitError)
+		}
+	},
+}
+
+func init() {
+	channelCmd.AddCommand(channelDiffCmd)
+
+	channelDiffCmd.Flags().String("output", "text", "Output format. text or json")
+	channelDiffCmd.Flags().Bool("exit-code", false, "Exit with 1 when there are differences, and 2 on errors")
+	channelDiffCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/tidwall/sjson"
+)
+
+// EventFilter selects the channel events to show by their type and by the member which they concern.
+type EventFilter struct {
+	types  map[skyway.EventType]bool
+	member string
+	// names maps the ids of members to their names, so that the member can be given by name.
+	names map[string]string
+	// publishers maps the ids of publications to their publishers,
+	// so that the events of a publication without its publisher, such as StreamUnpublished, match the publisher.
+	publishers map[string]string
+}
+
+// NewEventFilter returns a filter which matches the events of types concerning member.
+// All types match when types is empty, and all members match when member is empty.
+// member is either the id or the name of a member.
+func NewEventFilter(types []string, member string) (*EventFilter, error) {
+	filter := &EventFilter{member: member, names: map[string]string{}, publishers: map[string]string{}}
+	for _, name := range types {
+		eventType, err := skyway.ParseEventType(name)
+		if err != nil {
+			return nil, err
+		}
+		if filter.types == nil {
+			filter.types = map[skyway.EventType]bool{}
+		}
+		filter.types[eventType] = true
+	}
+	return filter, nil
+}
+
+// Learn records the names of the members and the publishers of the publications in the channel,
+// so that events which have only member ids match by name, and events which have only publication ids match by publisher.
+func (f *EventFilter) Learn(channel skyway.Channel) {
+	for _, member := range channel.Members {
+		if member.Name != "" {
+			f.names[member.Id] = member.Name
+		}
+	}
+	for _, publication := range channel.Publications {
+		if publication.PublisherId != "" {
+			f.publishers[publication.Id] = publication.PublisherId
+		}
+	}
+}
+
+// Match reports whether the event should be shown.
+// The names of members and the publishers of publications in the events are recorded, so events must be given in the order they occurred.
+func (f *EventFilter) Match(event *skyway.Event) bool {
+	if event.Member != nil && event.Member.Name != "" {
+		f.names[event.Member.Id] = event.Member.Name
+	}
+	if event.Publication != nil && event.Publication.PublisherId != "" {
+		f.publishers[event.Publication.Id] = event.Publication.PublisherId
+	}
+
+	if f.types != nil && !f.types[event.Type] {
+		return false
+	}
+	if f.member == "" {
+		return true
+	}
+	ids := event.MemberIds()
+	if event.Publication != nil {
+		ids = append(ids, f.publishers[event.Publication.Id])
+	}
+	if event.Subscription != nil {
+		ids = append(ids, f.publishers[event.Subscription.PublicationId])
+	}
+	for _, id := range ids {
+		if id != "" && (id == f.member || f.names[id] == f.member) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventPrinter prints channel events in the output format of "channel watch" and "channel replay".
+type eventPrinter struct {
+	w        io.Writer
+	output   string
+	pretty   bool
+	timeline *Timeline
+}
+
+// newEventPrinter returns a printer for output, which is json or timeline.
+func newEventPrinter(w io.Writer, output string, pretty bool) (*eventPrinter, error) {
+	if output != "json" && output != "timeline" {
+		return nil, fmt.Errorf("--output should be json or timeline. value: %s", output)
+	}
+	return &eventPrinter{w: w, output: output, pretty: pretty, timeline: NewTimeline()}, nil
+}
+
+// Learn records the snapshot of the channel to resolve the ids in its events.
+func (p *eventPrinter) Learn(channel skyway.Channel) {
+	p.timeline.Learn(channel)
+}
+
+func (p *eventPrinter) Print(event *skyway.Event) error {
+	if p.output == "timeline" {
+		p.timeline.Render(p.w, event.ReceivedAt, event)
+		return nil
+	}
+
+	// the event is tagged with its channel, because the notification may not have it
+	message, err := sjson.SetBytes(event.Raw, "channelId", event.ChannelId)
+	if err != nil {
+		return err
+	}
+	if p.pretty {
+		var buffer bytes.Buffer
+		if err := json.Indent(&buffer, message, "", "  "); err != nil {
+			return err
+		}
+		message = buffer.Bytes()
+	}
+	_, err = fmt.Fprintln(p.w, string(message))
+	return err
+}
+
+// EventRecord is a line of the NDJSON file written by "channel watch --record".
+// It has either an event as notified by the RTC API, or a snapshot of a channel taken before watching.
+type EventRecord struct {
+	ReceivedAt time.Time       `json:"receivedAt"`
+	ChannelId  string          `json:"channelId"`
+	Event      json.RawMessage `json:"event,omitempty"`
+	Snapshot   *skyway.Channel `json:"snapshot,omitempty"`
+}
+
+// Parse returns the event of the record with its channel and the time it was received, or nil when the record is a snapshot.
+func (r EventRecord) Parse() (*skyway.Event, error) {
+	if r.Event == nil {
+		return nil, nil
+	}
+	event, err := skyway.ParseEvent(r.Event)
+	if err != nil || event == nil {
+		return nil, err
+	}
+	event.ChannelId = r.ChannelId
+	event.ReceivedAt = r.ReceivedAt
+	return event, nil
+}
+
+// eventRecorder writes events and snapshots to an NDJSON file.
+// Each record is written as soon as it is given, so that the file is complete when the command is interrupted.
+type eventRecorder struct {
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func newEventRecorder(path string) (*eventRecorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create record file: %w", err)
+	}
+	return &eventRecorder{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+func (r *eventRecorder) RecordSnapshot(channel skyway.Channel, takenAt time.Time) error {
+	return r.encoder.Encode(EventRecord{ReceivedAt: takenAt, ChannelId: channel.Id, Snapshot: &channel})
+}
+
+func (r *eventRecorder) RecordEvent(event *skyway.Event) error {
+	return r.encoder.Encode(EventRecord{ReceivedAt: event.ReceivedAt, ChannelId: event.ChannelId, Event: event.Raw})
+}
+
+func (r *eventRecorder) Close() error {
+	return r.file.Close()
+}
+
+// ReplayEventRecords reads the records written by "channel watch --record" from r, and calls handle with each of them
+// at the intervals they were received, divided by speed. The records are handled without waiting when speed is 0.
+// It returns ctx.Err() when ctx is done before all records are handled.
+func ReplayEventRecords(ctx context.Context, r io.Reader, speed float64, handle func(record EventRecord) error) error {
+	if speed < 0 {
+		return fmt.Errorf("speed should be 0 or more. value: %v", speed)
+	}
+
+	reader := bufio.NewReader(r)
+	var previous time.Time
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			if err == io.EOF {
+				return nil
+			}
+			continue
+		}
+
+		var record EventRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("invalid record. line: %d err: %v", line, err)
+		}
+
+		if speed > 0 && !previous.IsZero() && record.ReceivedAt.After(previous) {
+			timer := time.NewTimer(time.Duration(float64(record.ReceivedAt.Sub(previous)) / speed))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		previous = record.ReceivedAt
+
+		if err := handle(record); err != nil {
+			return err
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
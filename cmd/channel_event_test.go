@@ -0,0 +1,135 @@
+package cmd_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/cmd"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+func TestEventFilter(t *testing.T) {
+	memberAdded := &skyway.Event{Type: skyway.EventMemberAdded, Member: &skyway.Member{Id: "m1", Name: "alice"}}
+	published := &skyway.Event{Type: skyway.EventStreamPublished, Publication: &skyway.Publication{Id: "p1", PublisherId: "m1"}}
+	subscribed := &skyway.Event{Type: skyway.EventStreamSubscribed, Subscription: &skyway.Subscription{Id: "s1", PublicationId: "p2", SubscriberId: "m2"}}
+
+	t.Run("指定した種類のイベントだけを選ぶ", func(t *testing.T) {
+		filter, err := cmd.NewEventFilter([]string{"memberAdded", "StreamPublished"}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !filter.Match(memberAdded) || !filter.Match(published) || filter.Match(subscribed) {
+			t.Error("unexpected match")
+		}
+	})
+
+	t.Run("メンバーの名前で選ぶ", func(t *testing.T) {
+		filter, err := cmd.NewEventFilter(nil, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the name of m1 is learned from MemberAdded
+		if !filter.Match(memberAdded) || !filter.Match(published) || filter.Match(subscribed) {
+			t.Error("unexpected match")
+		}
+	})
+
+	t.Run("スナップショットのメンバー名とIDで選ぶ", func(t *testing.T) {
+		filter, err := cmd.NewEventFilter(nil, "bob")
+		if err != nil {
+			t.Fatal(err)
+		}
+		filter.Learn(testChannel)
+		if !filter.Match(subscribed) || filter.Match(published) {
+			t.Error("unexpected match")
+		}
+
+		filter, err = cmd.NewEventFilter(nil, "m2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !filter.Match(subscribed) {
+			t.Error("unexpected match")
+		}
+	})
+
+	t.Run("公開者のないパブリケーションのイベントは公開者で選ぶ", func(t *testing.T) {
+		filter, err := cmd.NewEventFilter(nil, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		unpublished := &skyway.Event{Type: skyway.EventStreamUnpublished, Publication: &skyway.Publication{Id: "p1"}}
+		disabled := &skyway.Event{Type: skyway.EventPublicationDisabled, Publication: &skyway.Publication{Id: "p2"}}
+		// the publisher of p1 is learned from StreamPublished
+		if !filter.Match(memberAdded) || !filter.Match(published) || !filter.Match(unpublished) || filter.Match(disabled) {
+			t.Error("unexpected match")
+		}
+
+		// the publisher of p2 is learned from the snapshot
+		filter, err = cmd.NewEventFilter(nil, "m1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		filter.Learn(testChannel)
+		if !filter.Match(disabled) {
+			t.Error("unexpected match")
+		}
+	})
+
+	t.Run("不明な種類はエラーになる", func(t *testing.T) {
+		if _, err := cmd.NewEventFilter([]string{"MemberJoined"}, ""); err == nil {
+			t.Error("err is nil")
+		}
+	})
+}
+
+func TestReplayEventRecords(t *testing.T) {
+	records := strings.Join([]string{
+		`{"receivedAt":"2024-01-02T12:03:04Z","channelId":"c1","snapshot":{"id":"c1","name":"room","members":[{"id":"m1","name":"alice"}]}}`,
+		`{"receivedAt":"2024-01-02T12:03:05Z","channelId":"c1","event":{"jsonrpc":"2.0","method":"channelEventNotification","params":{"event":{"type":"MemberRemoved","data":{"member":{"id":"m1"}}}}}}`,
+		``,
+	}, "\n")
+
+	t.Run("記録したイベントを受信時刻と共に読み出す", func(t *testing.T) {
+		var handled []cmd.EventRecord
+		err := cmd.ReplayEventRecords(context.Background(), strings.NewReader(records), 0, func(record cmd.EventRecord) error {
+			handled = append(handled, record)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(handled) != 2 || handled[0].Snapshot == nil || handled[0].Snapshot.Members[0].Name != "alice" {
+			t.Fatalf("records: %+v", handled)
+		}
+
+		event, err := handled[1].Parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Type != skyway.EventMemberRemoved || event.ChannelId != "c1" || !event.ReceivedAt.Equal(time.Date(2024, 1, 2, 12, 3, 5, 0, time.UTC)) {
+			t.Errorf("event: %+v", event)
+		}
+	})
+
+	t.Run("受信間隔を速度で割った間隔で再生する", func(t *testing.T) {
+		start := time.Now()
+		err := cmd.ReplayEventRecords(context.Background(), strings.NewReader(records), 10, func(record cmd.EventRecord) error {
+			return nil
+		})
+		if elapsed := time.Since(start); err != nil || elapsed < 100*time.Millisecond || elapsed > time.Second {
+			t.Errorf("err: %v elapsed: %s", err, elapsed)
+		}
+	})
+
+	t.Run("不正な行はエラーになる", func(t *testing.T) {
+		err := cmd.ReplayEventRecords(context.Background(), strings.NewReader("{}\nnot json\n"), 0, func(record cmd.EventRecord) error {
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), "line: 2") {
+			t.Errorf("err: %v", err)
+		}
+	})
+}
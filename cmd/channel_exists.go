@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelExistsCmd represents the exists command
+var channelExistsCmd = &cobra.Command{
+	Use:   "exists [id]",
+	Short: "Check whether a channel exists",
+	Long: `Check whether a channel exists by id or name, for shell conditionals.
+Nothing is printed. The exit code is 0 when the channel exists and 1 when it does not.
+Like "channel diff", errors exit with 2 instead of 1, so that they are not taken as a missing channel,
+while unauthorized, timeout and interrupted keep their exit codes.
+
+Example:
+
+  if skyway-cli channel exists --name room; then ...`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeChannelIds,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		name, err := cmd.Flags().GetString("name")
+		checkErrWithCode(err, exitDiffError)
+
+		var id string
+		if len(args) == 1 {
+			id = args[0]
+		}
+
+		client := newChannelClient(appId, secretKey, url)
+
+		_, err = findChannel(cmd.Context(), client, id, name)
+		if errors.Is(err, skyway.ErrNotFound) {
+			cancelTimeout()
+			os.Exit(exitError)
+		}
+		checkErrWithCode(err, exitDiffError)
+	},
+}
+
+func init() {
+	channelCmd.AddCommand(channelExistsCmd)
+
+	channelExistsCmd.Flags().String("name", "", "Channel name")
+	channelExistsCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/kadoshita/skyway-cli/internal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -22,28 +21,29 @@ var channelFindCmd = &cobra.Command{
 		url := viper.GetString("skyway.channel.url")
 
 		id, err := cmd.Flags().GetString("id")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		name, err := cmd.Flags().GetString("name")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		pretty, err := cmd.Flags().GetBool("pretty")
-		cobra.CheckErr(err)
+		checkErr(err)
 
-		token, err := GenerateAdminToken(appId, secretKey, 3600, []string{})
-		cobra.CheckErr(err)
+		client := newChannelClient(appId, secretKey, url)
 
-		channel, err := internal.GetChannel(id, name, token, url)
-		cobra.CheckErr(err)
+		channel, err := client.FindChannel(cmd.Context(), id, name)
+		checkErr(err)
+
+		recordChannel(appId, channel, "find")
 
 		if pretty {
 			jsonString, err := json.MarshalIndent(channel, "", "  ")
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		} else {
 			jsonString, err := json.Marshal(channel)
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		}
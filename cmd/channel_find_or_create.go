@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type findOrCreateChannelOutput struct {
+	Channel skyway.Channel `json:"channel"`
+	Created bool           `json:"created"`
+}
+
+// channelFindOrCreateCmd represents the find-or-create command
+var channelFindOrCreateCmd = &cobra.Command{
+	Use:   "find-or-create",
+	Short: "Find a channel by name, or create it if it does not exist",
+	Long: `Find a channel by name, or create it if it does not exist.
+The output contains the channel and "created", which is true when the channel was created by this command.
+"created" is best-effort: when another client creates the channel at the same moment, it may be true although this command did not create it.
+The metadata is only used when the channel is created.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		channelName, err := cmd.Flags().GetString("name")
+		checkErr(err)
+		metadata, err := cmd.Flags().GetString("metadata")
+		checkErr(err)
+
+		if channelName == "" {
+			checkErr(fmt.Errorf("--name is required"))
+		}
+
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, created, err := client.FindOrCreateChannel(cmd.Context(), channelName, metadata)
+		checkErr(err)
+
+		if created {
+			recordChannel(appId, channel, "create")
+		} else {
+			recordChannel(appId, channel, "find")
+		}
+
+		printJSON(findOrCreateChannelOutput{Channel: channel, Created: created}, pretty)
+	},
+}
+
+func init() {
+	channelCmd.AddCommand(channelFindOrCreateCmd)
+
+	channelFindOrCreateCmd.Flags().String("metadata", "", "Channel metadata")
+	channelFindOrCreateCmd.Flags().String("name", "", "Channel name")
+	channelFindOrCreateCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -3,8 +3,8 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
-	"github.com/kadoshita/skyway-cli/internal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -17,7 +17,16 @@ func GetChannel(channelId string, token string) (string, error) {
 var channelGetCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get a channel",
-	Args:  cobra.ExactArgs(1),
+	Long: `Get a channel by id.
+With --output tree, the channel is shown as members, their publications and the subscribers of each publication.
+With --output table, the channel is shown as a table with one row per member.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeChannelIds(cmd, args, toComplete)
+	},
 	PreRun: func(cmd *cobra.Command, args []string) {
 		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
 	},
@@ -29,22 +38,38 @@ var channelGetCmd = &cobra.Command{
 		url := viper.GetString("skyway.channel.url")
 
 		pretty, err := cmd.Flags().GetBool("pretty")
-		cobra.CheckErr(err)
+		checkErr(err)
+
+		output, err := cmd.Flags().GetString("output")
+		checkErr(err)
+		if output != "json" && output != "tree" && output != "table" {
+			checkErr(fmt.Errorf("--output should be json, tree or table. value: %s", output))
+		}
 
-		token, err := GenerateAdminToken(appId, secretKey, 3600, []string{})
-		cobra.CheckErr(err)
+		client := newChannelClient(appId, secretKey, url)
 
-		channel, err := internal.GetChannel(id, "", token, url)
-		cobra.CheckErr(err)
+		channel, err := client.FindChannel(cmd.Context(), id, "")
+		checkErr(err)
+
+		recordChannel(appId, channel, "find")
+
+		switch output {
+		case "tree":
+			RenderChannelTree(os.Stdout, channel)
+			return
+		case "table":
+			checkErr(RenderChannelTable(os.Stdout, channel))
+			return
+		}
 
 		if pretty {
 			jsonString, err := json.MarshalIndent(channel, "", "  ")
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		} else {
 			jsonString, err := json.Marshal(channel)
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		}
@@ -54,5 +79,6 @@ var channelGetCmd = &cobra.Command{
 func init() {
 	channelCmd.AddCommand(channelGetCmd)
 
+	channelGetCmd.Flags().String("output", "json", "Output format. json, tree or table")
 	channelGetCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
 }
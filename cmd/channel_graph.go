@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type graphNode struct {
+	id    string
+	label string
+	isBot bool
+}
+
+type graphEdge struct {
+	from  string
+	to    string
+	label string
+	// forwarding is an edge from the origin publisher to the SFU bot which forwards the publication.
+	forwarding bool
+	disabled   bool
+}
+
+// channelGraph converts the channel into members as nodes and publications and subscriptions as edges.
+func channelGraph(channel skyway.Channel) ([]graphNode, []graphEdge) {
+	nodeIds := map[string]string{}
+	var nodes []graphNode
+	addNode := func(memberId string, label string, isBot bool) string {
+		if id, ok := nodeIds[memberId]; ok {
+			return id
+		}
+		id := fmt.Sprintf("m%d", len(nodes))
+		nodeIds[memberId] = id
+		nodes = append(nodes, graphNode{id: id, label: label, isBot: isBot})
+		return id
+	}
+
+	for _, member := range channel.Members {
+		label := member.Name
+		if label == "" {
+			label = member.Id
+		}
+		if member.Subtype != "" && member.Subtype != "person" {
+			label += " (" + member.Subtype + ")"
+		}
+		addNode(member.Id, label, member.Type == "bot")
+	}
+	node := func(memberId string) string {
+		// members which already left the channel may still be referenced
+		return addNode(memberId, memberId, false)
+	}
+
+	publications := map[string]skyway.Publication{}
+	var edges []graphEdge
+	for _, publication := range channel.Publications {
+		publications[publication.Id] = publication
+		if publication.OriginId != "" && publication.OriginPublisherId != "" {
+			edges = append(edges, graphEdge{
+				from:       node(publication.OriginPublisherId),
+				to:         node(publication.PublisherId),
+				label:      "forwards " + publication.ContentType + " " + publication.OriginId,
+				forwarding: true,
+				disabled:   !publication.IsEnabled,
+			})
+		}
+	}
+
+	for _, subscription := range channel.Subscriptions {
+		publication, ok := publications[subscription.PublicationId]
+		if !ok {
+			continue
+		}
+		edges = append(edges, graphEdge{
+			from:     node(publication.PublisherId),
+			to:       node(subscription.SubscriberId),
+			label:    publication.ContentType + " " + publication.Id,
+			disabled: !publication.IsEnabled,
+		})
+	}
+
+	return nodes, edges
+}
+
+// RenderChannelDot writes the media topology of the channel in Graphviz DOT format.
+func RenderChannelDot(w io.Writer, channel skyway.Channel) {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
+	nodes, edges := channelGraph(channel)
+
+	fmt.Fprintf(w, "digraph \"%s\" {\n", escape(channel.Id))
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintf(w, "  label=\"%s\";\n", escape(channel.Name))
+	for _, n := range nodes {
+		shape := "box"
+		if n.isBot {
+			shape = "hexagon"
+		}
+		fmt.Fprintf(w, "  %s [label=\"%s\", shape=%s];\n", n.id, escape(n.label), shape)
+	}
+	for _, e := range edges {
+		var attributes []string
+		attributes = append(attributes, fmt.Sprintf("label=\"%s\"", escape(e.label)))
+		if e.forwarding {
+			attributes = append(attributes, "style=dashed", "color=blue")
+		}
+		if e.disabled {
+			attributes = append(attributes, "fontcolor=gray", "color=gray")
+		}
+		fmt.Fprintf(w, "  %s -> %s [%s];\n", e.from, e.to, strings.Join(attributes, ", "))
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// RenderChannelMermaid writes the media topology of the channel as a Mermaid flowchart.
+func RenderChannelMermaid(w io.Writer, channel skyway.Channel) {
+	escape := strings.NewReplacer(`"`, "#quot;").Replace
+	nodes, edges := channelGraph(channel)
+
+	fmt.Fprintln(w, "graph LR")
+	for _, n := range nodes {
+		if n.isBot {
+			fmt.Fprintf(w, "  %s{{\"%s\"}}\n", n.id, escape(n.label))
+		} else {
+			fmt.Fprintf(w, "  %s[\"%s\"]\n", n.id, escape(n.label))
+		}
+	}
+
+	var disabledEdges []string
+	for i, e := range edges {
+		arrow := "-->"
+		if e.forwarding {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(w, "  %s %s|\"%s\"| %s\n", e.from, arrow, escape(e.label), e.to)
+		if e.disabled {
+			disabledEdges = append(disabledEdges, fmt.Sprint(i))
+		}
+	}
+	if len(disabledEdges) > 0 {
+		fmt.Fprintf(w, "  linkStyle %s stroke:gray\n", strings.Join(disabledEdges, ","))
+	}
+}
+
+// channelGraphCmd represents the graph command
+var channelGraphCmd = &cobra.Command{
+	Use:   "graph <id>",
+	Short: "Export the media topology of a channel as a graph",
+	Long: `Export the media topology of a channel as a Graphviz DOT or Mermaid graph.
+Members are nodes, and subscriptions are edges from the publisher to the subscriber.
+Publications forwarded by SFU bots are drawn as dashed edges from the origin publisher to the bot.
+Edges of disabled publications are drawn in gray.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeChannelIds,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		format, err := cmd.Flags().GetString("format")
+		checkErr(err)
+		if format != "dot" && format != "mermaid" {
+			checkErr(fmt.Errorf("--format should be dot or mermaid. value: %s", format))
+		}
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, args[0], "")
+		checkErr(err)
+
+		if format == "dot" {
+			RenderChannelDot(os.Stdout, channel)
+		} else {
+			RenderChannelMermaid(os.Stdout, channel)
+		}
+	},
+}
+
+func init() {
+	channelCmd.AddCommand(channelGraphCmd)
+
+	channelGraphCmd.Flags().String("format", "dot", "Graph format. dot or mermaid")
+	channelGraphCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
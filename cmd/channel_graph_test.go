@@ -0,0 +1,49 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kadoshita/skyway-cli/cmd"
+)
+
+func TestRenderChannelGraph(t *testing.T) {
+	t.Run("DOT形式で転送されたパブリケーションを破線で表示する", func(t *testing.T) {
+		var buffer bytes.Buffer
+		cmd.RenderChannelDot(&buffer, testChannel)
+
+		expected := strings.Join([]string{
+			`digraph "c1" {`,
+			`  rankdir=LR;`,
+			`  label="room";`,
+			`  m0 [label="alice", shape=box];`,
+			`  m1 [label="bob", shape=box];`,
+			`  m2 [label="m3 (sfu)", shape=hexagon];`,
+			`  m0 -> m2 [label="forwards video p1", style=dashed, color=blue];`,
+			`  m2 -> m1 [label="video p3"];`,
+			`}`,
+			``,
+		}, "\n")
+		if buffer.String() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, buffer.String())
+		}
+	})
+	t.Run("Mermaid形式で転送されたパブリケーションを点線で表示する", func(t *testing.T) {
+		var buffer bytes.Buffer
+		cmd.RenderChannelMermaid(&buffer, testChannel)
+
+		expected := strings.Join([]string{
+			`graph LR`,
+			`  m0["alice"]`,
+			`  m1["bob"]`,
+			`  m2{{"m3 (sfu)"}}`,
+			`  m0 -.->|"forwards video p1"| m2`,
+			`  m2 -->|"video p3"| m1`,
+			``,
+		}, "\n")
+		if buffer.String() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, buffer.String())
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelListCmd represents the list command
+var channelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List channels created, found or watched by this CLI",
+	Long: `List channels created, found or watched by this CLI.
+The Channel API has no list operation, so the channels are read from a local registry
+stored in $XDG_DATA_HOME/skyway-cli/channels.json (~/.local/share/skyway-cli/channels.json by default).
+With --refresh, each channel is looked up and the ones which no longer exist are marked as deleted.
+Only the channels of the configured App ID are listed when it is set.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		refresh, err := cmd.Flags().GetBool("refresh")
+		checkErr(err)
+
+		all, err := cmd.Flags().GetBool("all")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		path, err := internal.RegistryPath()
+		checkErr(err)
+
+		registry, err := internal.LoadRegistry(path)
+		checkErr(err)
+
+		if refresh {
+			client := newChannelClient(appId, secretKey, url)
+
+			// the channels are looked up without the lock of the registry, and the results are applied at once
+			var found []skyway.Channel
+			var deleted []string
+			for _, entry := range registry.Channels {
+				if entry.AppId != appId || entry.DeletedAt != nil {
+					continue
+				}
+
+				channel, err := client.FindChannel(cmd.Context(), entry.Id, "")
+				if errors.Is(err, skyway.ErrNotFound) {
+					deleted = append(deleted, entry.Id)
+					continue
+				}
+				checkErr(err)
+
+				found = append(found, channel)
+			}
+
+			registry, err = internal.UpdateRegistry(path, func(registry *internal.Registry) error {
+				now := time.Now()
+				for _, id := range deleted {
+					registry.MarkDeleted(appId, id, now)
+				}
+				for _, channel := range found {
+					registry.Record(appId, channel, "find", now)
+				}
+				return nil
+			})
+			checkErr(err)
+		}
+
+		entries := []internal.RegistryEntry{}
+		for _, entry := range registry.Channels {
+			if appId != "" && entry.AppId != appId {
+				continue
+			}
+			if entry.DeletedAt != nil && !all {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+
+		printJSON(entries, pretty)
+	},
+}
+
+func init() {
+	channelCmd.AddCommand(channelListCmd)
+
+	channelListCmd.Flags().Bool("refresh", false, "Look up each channel and mark deleted ones")
+	channelListCmd.Flags().BoolP("all", "a", false, "Include deleted channels")
+	channelListCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+)
+
+// findMember looks up a member of the channel by id or name.
+func findMember(channel skyway.Channel, id string, name string) (skyway.Member, error) {
+	if id == "" && name == "" {
+		return skyway.Member{}, fmt.Errorf("member id or name is required")
+	}
+
+	for _, member := range channel.Members {
+		if (id != "" && member.Id == id) || (id == "" && member.Name == name) {
+			return member, nil
+		}
+	}
+	if id != "" {
+		return skyway.Member{}, fmt.Errorf("member %w. channel: %s id: %s", skyway.ErrNotFound, channel.Id, id)
+	}
+	return skyway.Member{}, fmt.Errorf("member %w. channel: %s name: %s", skyway.ErrNotFound, channel.Id, name)
+}
+
+// channelMemberCmd represents the member command
+var channelMemberCmd = &cobra.Command{
+	Use:   "member",
+	Short: "Channel member operations",
+}
+
+func init() {
+	channelCmd.AddCommand(channelMemberCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelMemberAddCmd represents the member add command
+var channelMemberAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a member to a channel",
+	Long: `Add a member to a channel.
+The added member is printed as JSON.
+With --ttl, the member expires after the given seconds unless its TTL is updated.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		name, err := cmd.Flags().GetString("name")
+		checkErr(err)
+
+		memberType, err := cmd.Flags().GetString("type")
+		checkErr(err)
+
+		subtype, err := cmd.Flags().GetString("subtype")
+		checkErr(err)
+
+		metadata, err := cmd.Flags().GetString("metadata")
+		checkErr(err)
+
+		ttl, err := cmd.Flags().GetInt("ttl")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		params := skyway.AddMemberParams{
+			ChannelId: channel.Id,
+			Name:      name,
+			Type:      memberType,
+			Subtype:   subtype,
+			Metadata:  metadata,
+		}
+		if ttl > 0 {
+			params.TtlSec = time.Now().Add(time.Duration(ttl) * time.Second).Unix()
+		}
+
+		memberId, err := client.AddMember(cmd.Context(), params)
+		checkErr(err)
+
+		channel, err = findChannel(cmd.Context(), client, channel.Id, "")
+		checkErr(err)
+
+		member, err := findMember(channel, memberId, "")
+		checkErr(err)
+
+		printJSON(member, pretty)
+	},
+}
+
+func init() {
+	channelMemberCmd.AddCommand(channelMemberAddCmd)
+
+	channelMemberAddCmd.Flags().String("channel-id", "", "Channel id")
+	channelMemberAddCmd.Flags().String("channel-name", "", "Channel name")
+	channelMemberAddCmd.Flags().String("name", "", "Member name")
+	channelMemberAddCmd.Flags().String("type", "person", "Member type")
+	channelMemberAddCmd.Flags().String("subtype", "person", "Member subtype")
+	channelMemberAddCmd.Flags().String("metadata", "", "Member metadata")
+	channelMemberAddCmd.Flags().Int("ttl", 0, "Member TTL in seconds. The member does not expire when 0")
+	channelMemberAddCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// KeepaliveInterval returns how often the TTL is updated.
+// The TTL is updated at half of its length so that one failed update does not expire the member.
+func KeepaliveInterval(ttl time.Duration) time.Duration {
+	interval := ttl / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	return interval
+}
+
+// channelMemberKeepaliveCmd represents the member keepalive command
+var channelMemberKeepaliveCmd = &cobra.Command{
+	Use:   "keepalive <member-id>...",
+	Short: "Keep members alive by updating their TTL periodically",
+	Long: `Keep members alive by updating their TTL periodically until interrupted.
+The TTL is updated at half of the --ttl interval.
+With --leave, the members leave the channel when this command exits by SIGINT, SIGTERM or --timeout.
+The command exits with 130 when interrupted. With --timeout, it stops keeping the members alive after the duration and exits with 124,
+so do not give --timeout to keep them alive until interrupted.`,
+	Args: cobra.MinimumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		ttlSeconds, err := cmd.Flags().GetInt("ttl")
+		checkErr(err)
+		if ttlSeconds <= 0 {
+			checkErr(fmt.Errorf("--ttl should be greater than 0. value: %d", ttlSeconds))
+		}
+		ttl := time.Duration(ttlSeconds) * time.Second
+
+		leave, err := cmd.Flags().GetBool("leave")
+		checkErr(err)
+
+		// the admin token is renewed by the token source because this command runs longer than its expiry
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		for _, memberId := range args {
+			_, err := findMember(channel, memberId, "")
+			checkErr(err)
+		}
+
+		// the context is cancelled by SIGINT, SIGTERM or --timeout
+		ctx := cmd.Context()
+
+		updateTtl := func() {
+			ttlSec := time.Now().Add(ttl).Unix()
+			for _, memberId := range args {
+				if err := client.UpdateMemberTtl(ctx, channel.Id, memberId, ttlSec); err != nil {
+					slog.Warn("Failed to update member TTL", "channel", channel.Id, "member", memberId, "err", err)
+					continue
+				}
+				slog.Debug("Updated member TTL", "channel", channel.Id, "member", memberId, "ttlSec", ttlSec)
+			}
+		}
+
+		interval := KeepaliveInterval(ttl)
+		slog.Info("Keeping members alive", "channel", channel.Id, "members", args, "interval", interval)
+
+		updateTtl()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case <-ticker.C:
+				updateTtl()
+			}
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "shutting down...")
+		if leave {
+			// the members leave even though the context of the command is already cancelled
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+			defer cancel()
+			for _, memberId := range args {
+				if err := client.LeaveChannel(ctx, channel.Id, memberId); err != nil {
+					slog.Warn("Failed to leave channel", "channel", channel.Id, "member", memberId, "err", err)
+					continue
+				}
+				slog.Info("Left channel", "channel", channel.Id, "member", memberId)
+			}
+		}
+		exitIfDone(cmd.Context())
+	},
+}
+
+func init() {
+	channelMemberCmd.AddCommand(channelMemberKeepaliveCmd)
+
+	channelMemberKeepaliveCmd.Flags().String("channel-id", "", "Channel id")
+	channelMemberKeepaliveCmd.Flags().String("channel-name", "", "Channel name")
+	channelMemberKeepaliveCmd.Flags().Int("ttl", 60, "Member TTL in seconds")
+	channelMemberKeepaliveCmd.Flags().Bool("leave", false, "Leave the channel on exit")
+	channelMemberKeepaliveCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
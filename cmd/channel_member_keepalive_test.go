@@ -0,0 +1,29 @@
+package cmd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/cmd"
+)
+
+func TestKeepaliveInterval(t *testing.T) {
+	cases := []struct {
+		name     string
+		ttl      time.Duration
+		expected time.Duration
+	}{
+		{"TTLの半分の間隔で更新する", 60 * time.Second, 30 * time.Second},
+		{"TTLが奇数秒の場合も半分の間隔で更新する", 3 * time.Second, 1500 * time.Millisecond},
+		{"間隔が1秒の場合はそのまま", 2 * time.Second, time.Second},
+		{"間隔が1秒未満の場合は1秒にする", time.Second, time.Second},
+		{"TTLが0の場合は1秒にする", 0, time.Second},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := cmd.KeepaliveInterval(c.ttl); actual != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
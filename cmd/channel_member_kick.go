@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelMemberKickCmd represents the member kick command
+var channelMemberKickCmd = &cobra.Command{
+	Use:   "kick [member-id]",
+	Short: "Remove a member from a channel",
+	Long: `Remove a member from a channel by id or name.
+The removed member is printed as JSON.
+Without --yes, a confirmation prompt is shown before removing.`,
+	Args: cobra.MaximumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		name, err := cmd.Flags().GetString("name")
+		checkErr(err)
+
+		yes, err := cmd.Flags().GetBool("yes")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		var memberId string
+		if len(args) > 0 {
+			memberId = args[0]
+		}
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		member, err := findMember(channel, memberId, name)
+		checkErr(err)
+
+		if !yes {
+			ok, err := Confirm(cmd.Context(), cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("Remove member %s (name: %s) from channel %s?", member.Id, member.Name, channel.Id))
+			checkErr(err)
+			if !ok {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Aborted")
+				return
+			}
+		}
+
+		err = client.LeaveChannel(cmd.Context(), channel.Id, member.Id)
+		checkErr(err)
+
+		printJSON(member, pretty)
+	},
+}
+
+func init() {
+	channelMemberCmd.AddCommand(channelMemberKickCmd)
+
+	channelMemberKickCmd.Flags().String("channel-id", "", "Channel id")
+	channelMemberKickCmd.Flags().String("channel-name", "", "Channel name")
+	channelMemberKickCmd.Flags().String("name", "", "Member name")
+	channelMemberKickCmd.Flags().BoolP("yes", "y", false, "Remove without confirmation")
+	channelMemberKickCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelMemberLeaveCmd represents the member leave command
+var channelMemberLeaveCmd = &cobra.Command{
+	Use:   "leave <member-id>",
+	Short: "Leave a channel as a member",
+	Long: `Leave a channel as a member, typically one added by "channel member add".
+The member that left is printed as JSON.
+To remove another participant, use "channel member kick".`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		member, err := findMember(channel, args[0], "")
+		checkErr(err)
+
+		err = client.LeaveChannel(cmd.Context(), channel.Id, member.Id)
+		checkErr(err)
+
+		printJSON(member, pretty)
+	},
+}
+
+func init() {
+	channelMemberCmd.AddCommand(channelMemberLeaveCmd)
+
+	channelMemberLeaveCmd.Flags().String("channel-id", "", "Channel id")
+	channelMemberLeaveCmd.Flags().String("channel-name", "", "Channel name")
+	channelMemberLeaveCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelMemberMetadataCmd represents the member metadata command
+var channelMemberMetadataCmd = &cobra.Command{
+	Use:   "metadata <member-id> <metadata>",
+	Short: "Update member metadata",
+	Long: `Update member metadata.
+The updated member is printed as JSON.`,
+	Args: cobra.ExactArgs(2),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		isJson, err := cmd.Flags().GetBool("json")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		metadata := args[1]
+		if isJson {
+			checkErr(validateJSONMetadata(metadata))
+		}
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		member, err := findMember(channel, args[0], "")
+		checkErr(err)
+
+		err = client.UpdateMemberMetadata(cmd.Context(), channel.Id, member.Id, metadata)
+		checkErr(err)
+
+		channel, err = findChannel(cmd.Context(), client, channel.Id, "")
+		checkErr(err)
+
+		member, err = findMember(channel, member.Id, "")
+		checkErr(err)
+
+		printJSON(member, pretty)
+	},
+}
+
+func init() {
+	channelMemberCmd.AddCommand(channelMemberMetadataCmd)
+
+	channelMemberMetadataCmd.Flags().String("channel-id", "", "Channel id")
+	channelMemberMetadataCmd.Flags().String("channel-name", "", "Channel name")
+	channelMemberMetadataCmd.Flags().Bool("json", false, "Validate metadata as JSON")
+	channelMemberMetadataCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
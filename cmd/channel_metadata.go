@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func validateJSONMetadata(metadata string) error {
+	if !json.Valid([]byte(metadata)) {
+		return fmt.Errorf("metadata is not valid JSON")
+	}
+	return nil
+}
+
+// channelMetadataCmd represents the metadata command
+var channelMetadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Update channel metadata",
+}
+
+func init() {
+	channelCmd.AddCommand(channelMetadataCmd)
+}
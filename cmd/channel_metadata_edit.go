@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// editInEditor opens content in $EDITOR (or vi) and returns the edited content.
+func editInEditor(content string, pattern string) (string, error) {
+	// $EDITOR which has only spaces is taken as unset, as it has no command to run
+	editor := os.Getenv("EDITOR")
+	if strings.TrimSpace(editor) == "" {
+		editor = "vi"
+	}
+
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	// $EDITOR may contain arguments, e.g. "code --wait"
+	editorArgs := strings.Fields(editor)
+	editorCmd := exec.Command(editorArgs[0], append(editorArgs[1:], file.Name())...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run editor. editor: %s err: %v", editor, err)
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(edited), nil
+}
+
+// channelMetadataEditCmd represents the metadata edit command
+var channelMetadataEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit channel metadata in $EDITOR",
+	Long: `Edit channel metadata in $EDITOR.
+The current metadata is opened in $EDITOR (vi if not set), and sent only when it was changed.
+With --json, the metadata is formatted before editing and validated as JSON before sending.
+The updated channel is printed as JSON.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		id, err := cmd.Flags().GetString("id")
+		checkErr(err)
+
+		name, err := cmd.Flags().GetString("name")
+		checkErr(err)
+
+		isJson, err := cmd.Flags().GetBool("json")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, id, name)
+		checkErr(err)
+
+		current := channel.Metadata
+		pattern := "skyway-cli-metadata-*.txt"
+		if isJson {
+			pattern = "skyway-cli-metadata-*.json"
+			var formatted bytes.Buffer
+			if err := json.Indent(&formatted, []byte(current), "", "  "); err == nil {
+				current = formatted.String()
+			}
+		}
+
+		edited, err := editInEditor(current, pattern)
+		checkErr(err)
+
+		metadata := strings.TrimRight(edited, "\n")
+		if isJson {
+			checkErr(validateJSONMetadata(metadata))
+
+			// formatting only changes are not treated as changes
+			var compacted, currentCompacted bytes.Buffer
+			checkErr(json.Compact(&compacted, []byte(metadata)))
+			metadata = compacted.String()
+			if err := json.Compact(&currentCompacted, []byte(channel.Metadata)); err == nil && currentCompacted.String() == metadata {
+				metadata = channel.Metadata
+			}
+		}
+
+		if metadata == channel.Metadata {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Metadata not changed")
+			return
+		}
+
+		err = client.UpdateChannelMetadata(cmd.Context(), channel.Id, metadata)
+		checkErr(err)
+
+		channel, err = findChannel(cmd.Context(), client, channel.Id, "")
+		checkErr(err)
+
+		printJSON(channel, pretty)
+	},
+}
+
+func init() {
+	channelMetadataCmd.AddCommand(channelMetadataEditCmd)
+
+	channelMetadataEditCmd.Flags().String("id", "", "Channel id")
+	channelMetadataEditCmd.Flags().String("name", "", "Channel name")
+	channelMetadataEditCmd.Flags().Bool("json", false, "Format metadata as JSON before editing and validate it before sending")
+	channelMetadataEditCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelMetadataSetCmd represents the metadata set command
+var channelMetadataSetCmd = &cobra.Command{
+	Use:   "set <metadata>",
+	Short: "Set channel metadata",
+	Long: `Set channel metadata.
+When metadata is "-", it is read from stdin.
+The updated channel is printed as JSON.`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		id, err := cmd.Flags().GetString("id")
+		checkErr(err)
+
+		name, err := cmd.Flags().GetString("name")
+		checkErr(err)
+
+		isJson, err := cmd.Flags().GetBool("json")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		metadata := args[0]
+		if metadata == "-" {
+			stdinBytes, err := io.ReadAll(cmd.InOrStdin())
+			checkErr(err)
+			metadata = strings.TrimRight(string(stdinBytes), "\n")
+		}
+
+		if isJson {
+			checkErr(validateJSONMetadata(metadata))
+		}
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, id, name)
+		checkErr(err)
+
+		err = client.UpdateChannelMetadata(cmd.Context(), channel.Id, metadata)
+		checkErr(err)
+
+		channel, err = findChannel(cmd.Context(), client, channel.Id, "")
+		checkErr(err)
+
+		printJSON(channel, pretty)
+	},
+}
+
+func init() {
+	channelMetadataCmd.AddCommand(channelMetadataSetCmd)
+
+	channelMetadataSetCmd.Flags().String("id", "", "Channel id")
+	channelMetadataSetCmd.Flags().String("name", "", "Channel name")
+	channelMetadataSetCmd.Flags().Bool("json", false, "Validate metadata as JSON")
+	channelMetadataSetCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+// memberLabel returns "name (id)", or only the id when the member has no name or is unknown.
+func memberLabel(members map[string]skyway.Member, id string) string {
+	member, ok := members[id]
+	if !ok || member.Name == "" {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", member.Name, id)
+}
+
+func publicationLabel(publication skyway.Publication) string {
+	label := fmt.Sprintf("%s %s", publication.ContentType, publication.Id)
+	if !publication.IsEnabled {
+		label += " [disabled]"
+	}
+	if publication.OriginId != "" {
+		label += " [forwarded from " + publication.OriginId + "]"
+	}
+	return label
+}
+
+func membersById(channel skyway.Channel) map[string]skyway.Member {
+	members := map[string]skyway.Member{}
+	for _, member := range channel.Members {
+		members[member.Id] = member
+	}
+	return members
+}
+
+// RenderChannelTree writes the channel as a tree of members, their publications and the subscribers of each publication.
+func RenderChannelTree(w io.Writer, channel skyway.Channel) {
+	members := membersById(channel)
+
+	subscribers := map[string][]string{}
+	for _, subscription := range channel.Subscriptions {
+		subscribers[subscription.PublicationId] = append(subscribers[subscription.PublicationId], subscription.SubscriberId)
+	}
+
+	publications := map[string][]skyway.Publication{}
+	var orphans []skyway.Publication
+	for _, publication := range channel.Publications {
+		if _, ok := members[publication.PublisherId]; !ok {
+			orphans = append(orphans, publication)
+			continue
+		}
+		publications[publication.PublisherId] = append(publications[publication.PublisherId], publication)
+	}
+
+	if channel.Name == "" {
+		fmt.Fprintf(w, "channel %s\n", channel.Id)
+	} else {
+		fmt.Fprintf(w, "channel %s (%s)\n", channel.Name, channel.Id)
+	}
+
+	type node struct {
+		label        string
+		publications []skyway.Publication
+	}
+	var nodes []node
+	for _, member := range channel.Members {
+		label := memberLabel(members, member.Id)
+		if member.Type != "" {
+			label += " " + member.Type
+			if member.Subtype != "" {
+				label += "/" + member.Subtype
+			}
+		}
+		nodes = append(nodes, node{label: label, publications: publications[member.Id]})
+	}
+	if len(orphans) > 0 {
+		nodes = append(nodes, node{label: "(unknown publisher)", publications: orphans})
+	}
+
+	branch := func(last bool) (string, string) {
+		if last {
+			return "└── ", "    "
+		}
+		return "├── ", "│   "
+	}
+
+	for i, n := range nodes {
+		memberBranch, memberIndent := branch(i == len(nodes)-1)
+		fmt.Fprintln(w, memberBranch+n.label)
+
+		for j, publication := range n.publications {
+			publicationBranch, publicationIndent := branch(j == len(n.publications)-1)
+			fmt.Fprintln(w, memberIndent+publicationBranch+publicationLabel(publication))
+
+			publicationSubscribers := subscribers[publication.Id]
+			for k, subscriberId := range publicationSubscribers {
+				subscriberBranch, _ := branch(k == len(publicationSubscribers)-1)
+				fmt.Fprintln(w, memberIndent+publicationIndent+subscriberBranch+"subscribed by "+memberLabel(members, subscriberId))
+			}
+		}
+	}
+}
+
+// RenderChannelTable writes the channel as a table with one row per member.
+func RenderChannelTable(w io.Writer, channel skyway.Channel) error {
+	members := membersById(channel)
+
+	publishing := map[string][]string{}
+	publications := map[string]skyway.Publication{}
+	for _, publication := range channel.Publications {
+		publications[publication.Id] = publication
+
+		label := publication.ContentType
+		if !publication.IsEnabled {
+			label += "(disabled)"
+		}
+		publishing[publication.PublisherId] = append(publishing[publication.PublisherId], label)
+	}
+
+	subscribing := map[string][]string{}
+	for _, subscription := range channel.Subscriptions {
+		label := subscription.PublicationId
+		if publication, ok := publications[subscription.PublicationId]; ok {
+			label = publication.ContentType + " of "
+			if publisher, ok := members[publication.PublisherId]; ok && publisher.Name != "" {
+				label += publisher.Name
+			} else {
+				label += publication.PublisherId
+			}
+		}
+		subscribing[subscription.SubscriberId] = append(subscribing[subscription.SubscriberId], label)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tTYPE\tSUBTYPE\tPUBLISHING\tSUBSCRIBING\tMETADATA")
+	for _, member := range channel.Members {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			member.Id,
+			member.Name,
+			member.Type,
+			member.Subtype,
+			strings.Join(publishing[member.Id], ", "),
+			strings.Join(subscribing[member.Id], ", "),
+			member.Metadata,
+		)
+	}
+	return tw.Flush()
+}
+
+// Timeline renders channel events as one line each, resolving the ids in the events to names.
+// It remembers the members and publications in the events, so events must be rendered in the order they occurred.
+type Timeline struct {
+	// channels maps the ids of the channels to their names or ids, which label the lines when there are multiple channels.
+	channels     map[string]string
+	members      map[string]skyway.Member
+	publications map[string]skyway.Publication
+}
+
+// NewTimeline returns a timeline which resolves ids with the snapshots of the channels and the following events.
+func NewTimeline(channels ...skyway.Channel) *Timeline {
+	timeline := &Timeline{channels: map[string]string{}, members: map[string]skyway.Member{}, publications: map[string]skyway.Publication{}}
+	for _, channel := range channels {
+		timeline.Learn(channel)
+	}
+	return timeline
+}
+
+// Learn records the snapshot of the channel, so that the ids in its events are resolved.
+func (t *Timeline) Learn(channel skyway.Channel) {
+	t.channels[channel.Id] = channel.Name
+	if channel.Name == "" {
+		t.channels[channel.Id] = channel.Id
+	}
+	for _, member := range channel.Members {
+		t.members[member.Id] = member
+	}
+	for _, publication := range channel.Publications {
+		t.publications[publication.Id] = publication
+	}
+}
+
+// memberName returns the name of the member, or its id when the member has no name or is unknown.
+func (t *Timeline) memberName(id string) string {
+	if member, ok := t.members[id]; ok && member.Name != "" {
+		return member.Name
+	}
+	return id
+}
+
+func (t *Timeline) publicationName(id string) string {
+	if publication, ok := t.publications[id]; ok && publication.ContentType != "" {
+		return publication.ContentType + " " + id
+	}
+	return id
+}
+
+// Render writes the event received at receivedAt as a line such as "12:03:04 alice published video p1".
+// The time is written in the local time zone. With multiple channels, the line is labeled with the channel, such as "12:03:04 [room] alice joined".
+func (t *Timeline) Render(w io.Writer, receivedAt time.Time, event *skyway.Event) {
+	// learn the names before rendering, so that the event itself is resolved
+	if event.Member != nil {
+		member := t.members[event.Member.Id]
+		member.Id = event.Member.Id
+		if event.Member.Name != "" {
+			member.Name = event.Member.Name
+		}
+		t.members[member.Id] = member
+	}
+	if event.Publication != nil {
+		publication := t.publications[event.Publication.Id]
+		publication.Id = event.Publication.Id
+		if event.Publication.PublisherId != "" {
+			publication.PublisherId = event.Publication.PublisherId
+		}
+		if event.Publication.ContentType != "" {
+			publication.ContentType = event.Publication.ContentType
+		}
+		t.publications[publication.Id] = publication
+	}
+
+	label := ""
+	if len(t.channels) > 1 {
+		channel, ok := t.channels[event.ChannelId]
+		if !ok {
+			channel = event.ChannelId
+		}
+		label = "[" + channel + "] "
+	}
+	fmt.Fprintf(w, "%s %s%s\n", receivedAt.Local().Format(time.TimeOnly), label, t.describe(event))
+}
+
+func (t *Timeline) describe(event *skyway.Event) string {
+	switch {
+	case event.Member != nil:
+		name := t.memberName(event.Member.Id)
+		switch event.Type {
+		case skyway.EventMemberAdded:
+			return name + " joined"
+		case skyway.EventMemberRemoved:
+			return name + " left"
+		case skyway.EventMemberMetadataUpdated:
+			return fmt.Sprintf("%s updated metadata: %s", name, event.Member.Metadata)
+		}
+	case event.Publication != nil:
+		publisher := t.memberName(t.publications[event.Publication.Id].PublisherId)
+		publication := t.publicationName(event.Publication.Id)
+		switch event.Type {
+		case skyway.EventStreamPublished:
+			return fmt.Sprintf("%s published %s", publisher, publication)
+		case skyway.EventStreamUnpublished:
+			return fmt.Sprintf("%s unpublished %s", publisher, publication)
+		case skyway.EventPublicationEnabled:
+			return fmt.Sprintf("%s enabled %s", publisher, publication)
+		case skyway.EventPublicationDisabled:
+			return fmt.Sprintf("%s disabled %s", publisher, publication)
+		case skyway.EventPublicationMetadataUpdated:
+			return fmt.Sprintf("%s updated metadata of %s: %s", publisher, publication, event.Publication.Metadata)
+		}
+	case event.Subscription != nil:
+		subscriber := t.memberName(event.Subscription.SubscriberId)
+		publication := t.publicationName(event.Subscription.PublicationId)
+		publisherId := event.Subscription.PublisherId
+		if publisherId == "" {
+			publisherId = t.publications[event.Subscription.PublicationId].PublisherId
+		}
+		if publisherId != "" {
+			publication += " of " + t.memberName(publisherId)
+		}
+		switch event.Type {
+		case skyway.EventStreamSubscribed:
+			return fmt.Sprintf("%s subscribed %s", subscriber, publication)
+		case skyway.EventStreamUnsubscribed:
+			return fmt.Sprintf("%s unsubscribed %s", subscriber, publication)
+		}
+	case event.Type == skyway.EventChannelClosed:
+		return "channel closed"
+	case event.Type == skyway.EventChannelMetadataUpdated && event.Channel != nil:
+		return "channel metadata updated: " + event.Channel.Metadata
+	}
+
+	// unknown events are shown with their type and the members which they concern
+	description := string(event.Type)
+	for _, id := range event.MemberIds() {
+		description += " " + t.memberName(id)
+	}
+	return description
+}
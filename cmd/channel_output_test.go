@@ -0,0 +1,118 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/cmd"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+var testChannel = skyway.Channel{
+	Id:   "c1",
+	Name: "room",
+	Members: []skyway.Member{
+		{Id: "m1", Name: "alice", Type: "person", Subtype: "person"},
+		{Id: "m2", Name: "bob", Type: "person", Subtype: "person"},
+		{Id: "m3", Type: "bot", Subtype: "sfu"},
+	},
+	Publications: []skyway.Publication{
+		{Id: "p1", PublisherId: "m1", ContentType: "video", IsEnabled: true},
+		{Id: "p2", PublisherId: "m1", ContentType: "audio", IsEnabled: false},
+		{Id: "p3", PublisherId: "m3", ContentType: "video", IsEnabled: true, OriginId: "p1", OriginPublisherId: "m1"},
+	},
+	Subscriptions: []skyway.Subscription{
+		{Id: "s1", PublicationId: "p3", SubscriberId: "m2"},
+	},
+}
+
+func TestRenderChannelTree(t *testing.T) {
+	t.Run("メンバー、パブリケーション、サブスクライバーの木構造で表示する", func(t *testing.T) {
+		var buffer bytes.Buffer
+		cmd.RenderChannelTree(&buffer, testChannel)
+
+		expected := strings.Join([]string{
+			"channel room (c1)",
+			"├── alice (m1) person/person",
+			"│   ├── video p1",
+			"│   └── audio p2 [disabled]",
+			"├── bob (m2) person/person",
+			"└── m3 bot/sfu",
+			"    └── video p3 [forwarded from p1]",
+			"        └── subscribed by bob (m2)",
+			"",
+		}, "\n")
+		if buffer.String() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, buffer.String())
+		}
+	})
+}
+
+func TestRenderChannelTable(t *testing.T) {
+	t.Run("メンバーごとに1行で表示する", func(t *testing.T) {
+		var buffer bytes.Buffer
+		err := cmd.RenderChannelTable(&buffer, testChannel)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimRight(buffer.String(), "\n"), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("expected 4 lines, got %d:\n%s", len(lines), buffer.String())
+		}
+		if !strings.Contains(lines[1], "video, audio(disabled)") {
+			t.Errorf("publications of alice are not shown: %s", lines[1])
+		}
+		if !strings.Contains(lines[2], "video of m3") {
+			t.Errorf("subscriptions of bob are not shown: %s", lines[2])
+		}
+	})
+}
+
+func TestTimeline(t *testing.T) {
+	t.Run("イベントごとに時刻と名前を解決した1行で表示する", func(t *testing.T) {
+		timeline := cmd.NewTimeline(testChannel)
+		receivedAt := time.Date(2024, 1, 2, 12, 3, 4, 0, time.Local)
+
+		events := []*skyway.Event{
+			{Type: skyway.EventMemberAdded, Member: &skyway.Member{Id: "m4", Name: "carol"}},
+			{Type: skyway.EventStreamPublished, Publication: &skyway.Publication{Id: "p4", PublisherId: "m4", ContentType: "video"}},
+			{Type: skyway.EventStreamSubscribed, Subscription: &skyway.Subscription{Id: "s2", PublicationId: "p4", SubscriberId: "m2"}},
+			{Type: skyway.EventPublicationDisabled, Publication: &skyway.Publication{Id: "p1"}},
+			{Type: skyway.EventMemberRemoved, Member: &skyway.Member{Id: "m1"}},
+			{Type: "MemberTtlUpdated", Member: &skyway.Member{Id: "m2"}},
+		}
+		var buffer bytes.Buffer
+		for _, event := range events {
+			timeline.Render(&buffer, receivedAt, event)
+		}
+
+		expected := strings.Join([]string{
+			"12:03:04 carol joined",
+			"12:03:04 carol published video p4",
+			"12:03:04 bob subscribed video p4 of carol",
+			"12:03:04 alice disabled video p1",
+			"12:03:04 alice left",
+			"12:03:04 MemberTtlUpdated bob",
+			"",
+		}, "\n")
+		if buffer.String() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, buffer.String())
+		}
+	})
+	t.Run("複数のチャンネルの場合はチャンネル名を付けて表示する", func(t *testing.T) {
+		timeline := cmd.NewTimeline(testChannel, skyway.Channel{Id: "c2"})
+		receivedAt := time.Date(2024, 1, 2, 12, 3, 4, 0, time.Local)
+
+		var buffer bytes.Buffer
+		timeline.Render(&buffer, receivedAt, &skyway.Event{Type: skyway.EventMemberRemoved, ChannelId: "c1", Member: &skyway.Member{Id: "m2"}})
+		timeline.Render(&buffer, receivedAt, &skyway.Event{Type: skyway.EventMemberAdded, ChannelId: "c2", Member: &skyway.Member{Id: "m5", Name: "dave"}})
+
+		expected := "12:03:04 [room] bob left\n12:03:04 [c2] dave joined\n"
+		if buffer.String() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, buffer.String())
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+)
+
+// findPublication looks up a publication of the channel by id.
+func findPublication(channel skyway.Channel, id string) (skyway.Publication, error) {
+	for _, publication := range channel.Publications {
+		if publication.Id == id {
+			return publication, nil
+		}
+	}
+	return skyway.Publication{}, fmt.Errorf("publication %w. channel: %s id: %s", skyway.ErrNotFound, channel.Id, id)
+}
+
+// channelPublicationCmd represents the publication command
+var channelPublicationCmd = &cobra.Command{
+	Use:   "publication",
+	Short: "Channel publication operations",
+}
+
+func init() {
+	channelCmd.AddCommand(channelPublicationCmd)
+}
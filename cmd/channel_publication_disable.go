@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelPublicationDisableCmd represents the publication disable command
+var channelPublicationDisableCmd = &cobra.Command{
+	Use:   "disable <publication-id>",
+	Short: "Disable a publication",
+	Long: `Disable a publication.
+The updated publication is printed as JSON.`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		publication, err := findPublication(channel, args[0])
+		checkErr(err)
+
+		err = client.DisablePublication(cmd.Context(), channel.Id, publication.Id)
+		checkErr(err)
+
+		channel, err = findChannel(cmd.Context(), client, channel.Id, "")
+		checkErr(err)
+
+		publication, err = findPublication(channel, publication.Id)
+		checkErr(err)
+
+		printJSON(publication, pretty)
+	},
+}
+
+func init() {
+	channelPublicationCmd.AddCommand(channelPublicationDisableCmd)
+
+	channelPublicationDisableCmd.Flags().String("channel-id", "", "Channel id")
+	channelPublicationDisableCmd.Flags().String("channel-name", "", "Channel name")
+	channelPublicationDisableCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelPublicationMetadataCmd represents the publication metadata command
+var channelPublicationMetadataCmd = &cobra.Command{
+	Use:   "metadata <publication-id> <metadata>",
+	Short: "Update publication metadata",
+	Long: `Update publication metadata.
+The updated publication is printed as JSON.`,
+	Args: cobra.ExactArgs(2),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		isJson, err := cmd.Flags().GetBool("json")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		metadata := args[1]
+		if isJson {
+			checkErr(validateJSONMetadata(metadata))
+		}
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		publication, err := findPublication(channel, args[0])
+		checkErr(err)
+
+		err = client.UpdatePublicationMetadata(cmd.Context(), channel.Id, publication.Id, metadata)
+		checkErr(err)
+
+		channel, err = findChannel(cmd.Context(), client, channel.Id, "")
+		checkErr(err)
+
+		publication, err = findPublication(channel, publication.Id)
+		checkErr(err)
+
+		printJSON(publication, pretty)
+	},
+}
+
+func init() {
+	channelPublicationCmd.AddCommand(channelPublicationMetadataCmd)
+
+	channelPublicationMetadataCmd.Flags().String("channel-id", "", "Channel id")
+	channelPublicationMetadataCmd.Flags().String("channel-name", "", "Channel name")
+	channelPublicationMetadataCmd.Flags().Bool("json", false, "Validate metadata as JSON")
+	channelPublicationMetadataCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelPublicationPublishCmd represents the publication publish command
+var channelPublicationPublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Publish a stream on behalf of a member",
+	Long: `Publish a stream on behalf of a member.
+No media is sent; only the publication is created in the channel.
+The created publication is printed as JSON.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		publisherId, err := cmd.Flags().GetString("publisher-id")
+		checkErr(err)
+
+		publisherName, err := cmd.Flags().GetString("publisher-name")
+		checkErr(err)
+
+		contentType, err := cmd.Flags().GetString("content-type")
+		checkErr(err)
+		if contentType != "audio" && contentType != "video" && contentType != "data" {
+			checkErr(fmt.Errorf("--content-type should be audio, video or data. value: %s", contentType))
+		}
+
+		metadata, err := cmd.Flags().GetString("metadata")
+		checkErr(err)
+
+		origin, err := cmd.Flags().GetString("origin")
+		checkErr(err)
+
+		disabled, err := cmd.Flags().GetBool("disabled")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		publisher, err := findMember(channel, publisherId, publisherName)
+		checkErr(err)
+
+		params := skyway.PublishStreamParams{
+			ChannelId:   channel.Id,
+			PublisherId: publisher.Id,
+			ContentType: contentType,
+			Metadata:    metadata,
+			Origin:      origin,
+		}
+		if disabled {
+			isEnabled := false
+			params.IsEnabled = &isEnabled
+		}
+
+		publicationId, err := client.PublishStream(cmd.Context(), params)
+		checkErr(err)
+
+		channel, err = findChannel(cmd.Context(), client, channel.Id, "")
+		checkErr(err)
+
+		publication, err := findPublication(channel, publicationId)
+		checkErr(err)
+
+		printJSON(publication, pretty)
+	},
+}
+
+func init() {
+	channelPublicationCmd.AddCommand(channelPublicationPublishCmd)
+
+	channelPublicationPublishCmd.Flags().String("channel-id", "", "Channel id")
+	channelPublicationPublishCmd.Flags().String("channel-name", "", "Channel name")
+	channelPublicationPublishCmd.Flags().String("publisher-id", "", "Publisher member id")
+	channelPublicationPublishCmd.Flags().String("publisher-name", "", "Publisher member name")
+	channelPublicationPublishCmd.Flags().String("content-type", "video", "Content type. audio, video or data")
+	channelPublicationPublishCmd.Flags().String("metadata", "", "Publication metadata")
+	channelPublicationPublishCmd.Flags().String("origin", "", "Origin publication id, for a forwarded publication")
+	channelPublicationPublishCmd.Flags().Bool("disabled", false, "Publish in the disabled state")
+	channelPublicationPublishCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// channelReplayCmd represents the replay command
+var channelReplayCmd = &cobra.Command{
+	Use:   "replay <file>",
+	Short: "Replay channel events recorded by watch",
+	Long: `Replay the channel events recorded by "channel watch --record", and print them as "channel watch" does.
+The events are printed at the intervals they were received. With --speed, the intervals are divided by the speed,
+and the events are printed without waiting when it is 0.
+--output, --events and --member work as in "channel watch". When the file is -, the events are read from stdin.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		output, err := cmd.Flags().GetString("output")
+		checkErr(err)
+
+		eventTypes, err := cmd.Flags().GetStringSlice("events")
+		checkErr(err)
+
+		member, err := cmd.Flags().GetString("member")
+		checkErr(err)
+
+		speed, err := cmd.Flags().GetFloat64("speed")
+		checkErr(err)
+
+		filter, err := NewEventFilter(eventTypes, member)
+		checkErr(err)
+
+		printer, err := newEventPrinter(os.Stdout, output, pretty)
+		checkErr(err)
+
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			file, err := os.Open(args[0])
+			checkErr(err)
+			defer file.Close()
+			r = file
+		}
+
+		// the context is cancelled by SIGINT, SIGTERM or --timeout
+		err = ReplayEventRecords(cmd.Context(), r, speed, func(record EventRecord) error {
+			if record.Snapshot != nil {
+				filter.Learn(*record.Snapshot)
+				printer.Learn(*record.Snapshot)
+				return nil
+			}
+			event, err := record.Parse()
+			if err != nil || event == nil || !filter.Match(event) {
+				return err
+			}
+			return printer.Print(event)
+		})
+		checkErr(err)
+	},
+}
+
+func init() {
+	channelCmd.AddCommand(channelReplayCmd)
+
+	channelReplayCmd.Flags().String("output", "json", "Output format. json or timeline")
+	channelReplayCmd.Flags().StringSlice("events", []string{}, "Event types to print, such as MemberAdded,StreamPublished. All events are printed when empty")
+	channelReplayCmd.Flags().String("member", "", "Id or name of the member whose events are printed. Events of all members are printed when empty")
+	channelReplayCmd.Flags().Float64("speed", 1, "Speed of the replay. 2 replays twice as fast as recorded, and 0 replays without waiting")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type channelSnapshot struct {
+	TakenAt time.Time      `json:"takenAt"`
+	Channel skyway.Channel `json:"channel"`
+}
+
+// loadChannelSnapshot reads a snapshot file.
+// The output of "channel get" is also accepted as a snapshot without the time it was taken.
+func loadChannelSnapshot(path string) (channelSnapshot, error) {
+	var snapshot channelSnapshot
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return snapshot, fmt.Errorf("invalid snapshot. file: %s err: %v", path, err)
+	}
+	if _, ok := fields["channel"]; ok {
+		err = json.Unmarshal(data, &snapshot)
+	} else {
+		err = json.Unmarshal(data, &snapshot.Channel)
+	}
+	if err != nil {
+		return snapshot, fmt.Errorf("invalid snapshot. file: %s err: %v", path, err)
+	}
+	return snapshot, nil
+}
+
+// channelSnapshotCmd represents the snapshot command
+var channelSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <id>",
+	Short: "Save the current state of a channel",
+	Long: `Save the current state of a channel to a file, to compare it later with "channel diff".
+Without --output-file, the snapshot is printed to stdout.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeChannelIds,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		outputFile, err := cmd.Flags().GetString("output-file")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, args[0], "")
+		checkErr(err)
+
+		snapshot := channelSnapshot{TakenAt: time.Now(), Channel: channel}
+		if outputFile == "" {
+			printJSON(snapshot, pretty)
+			return
+		}
+
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		checkErr(err)
+
+		err = os.WriteFile(outputFile, append(data, '\n'), 0644)
+		checkErr(err)
+	},
+}
+
+func init() {
+	channelCmd.AddCommand(channelSnapshotCmd)
+
+	channelSnapshotCmd.Flags().StringP("output-file", "o", "", "File to save the snapshot")
+	channelSnapshotCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
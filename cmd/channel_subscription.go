@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+)
+
+// findSubscription looks up a subscription of the channel by id.
+func findSubscription(channel skyway.Channel, id string) (skyway.Subscription, error) {
+	for _, subscription := range channel.Subscriptions {
+		if subscription.Id == id {
+			return subscription, nil
+		}
+	}
+	return skyway.Subscription{}, fmt.Errorf("subscription %w. channel: %s id: %s", skyway.ErrNotFound, channel.Id, id)
+}
+
+// channelSubscriptionCmd represents the subscription command
+var channelSubscriptionCmd = &cobra.Command{
+	Use:   "subscription",
+	Short: "Channel subscription operations",
+}
+
+func init() {
+	channelCmd.AddCommand(channelSubscriptionCmd)
+}
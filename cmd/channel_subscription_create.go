@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// SubscribablePublications returns the publications in the channel which the member can subscribe to.
+// The member's own publications and the publications it already subscribes to are excluded.
+func SubscribablePublications(channel skyway.Channel, subscriberId string) []skyway.Publication {
+	subscribed := map[string]bool{}
+	for _, subscription := range channel.Subscriptions {
+		if subscription.SubscriberId == subscriberId {
+			subscribed[subscription.PublicationId] = true
+		}
+	}
+
+	var publications []skyway.Publication
+	for _, publication := range channel.Publications {
+		if publication.PublisherId == subscriberId || subscribed[publication.Id] {
+			continue
+		}
+		publications = append(publications, publication)
+	}
+	return publications
+}
+
+// ValidateSubscriptionTarget checks that exactly one of --publication-id and --all is given.
+func ValidateSubscriptionTarget(publicationId string, all bool) error {
+	if (publicationId == "") == !all {
+		return fmt.Errorf("either --publication-id or --all is required")
+	}
+	return nil
+}
+
+// channelSubscriptionCreateCmd represents the subscription create command
+var channelSubscriptionCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Subscribe to a publication on behalf of a member",
+	Long: `Subscribe to a publication on behalf of a member.
+With --all, the member subscribes to every publication in the channel,
+except its own publications and the ones it already subscribes to.
+The created subscriptions are printed as JSON, one per line.
+When some of the subscriptions fail, the others are still created and printed, and the command exits with an error after printing them.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		subscriberId, err := cmd.Flags().GetString("subscriber-id")
+		checkErr(err)
+
+		subscriberName, err := cmd.Flags().GetString("subscriber-name")
+		checkErr(err)
+
+		publicationId, err := cmd.Flags().GetString("publication-id")
+		checkErr(err)
+
+		all, err := cmd.Flags().GetBool("all")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		checkErr(ValidateSubscriptionTarget(publicationId, all))
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		subscriber, err := findMember(channel, subscriberId, subscriberName)
+		checkErr(err)
+
+		var publications []skyway.Publication
+		if all {
+			publications = SubscribablePublications(channel, subscriber.Id)
+			if len(publications) == 0 {
+				slog.Info("No publications to subscribe", "channel", channel.Id, "subscriber", subscriber.Id)
+				return
+			}
+		} else {
+			publication, err := findPublication(channel, publicationId)
+			checkErr(err)
+			publications = append(publications, publication)
+		}
+
+		// the subscriptions are created as far as possible, so that the ones created before a failure are printed to be seen and deleted
+		var created []skyway.Subscription
+		var failures []error
+		for _, publication := range publications {
+			subscriptionId, err := client.SubscribeStream(cmd.Context(), channel.Id, subscriber.Id, publication.Id)
+			if err != nil {
+				failures = append(failures, fmt.Errorf("failed to subscribe publication %s: %w", publication.Id, err))
+				continue
+			}
+			created = append(created, skyway.Subscription{
+				Id:            subscriptionId,
+				PublicationId: publication.Id,
+				SubscriberId:  subscriber.Id,
+				PublisherId:   publication.PublisherId,
+				ContentType:   publication.ContentType,
+			})
+		}
+
+		if len(created) > 0 {
+			// the subscriptions are printed as returned by the API, or as created when the channel cannot be found again
+			channel, err = findChannel(cmd.Context(), client, channel.Id, "")
+			if err != nil {
+				failures = append(failures, fmt.Errorf("failed to get the created subscriptions: %w", err))
+			}
+		}
+		for _, subscription := range created {
+			if found, err := findSubscription(channel, subscription.Id); err == nil {
+				subscription = found
+			}
+			printJSON(subscription, pretty)
+		}
+
+		checkErr(errors.Join(failures...))
+	},
+}
+
+func init() {
+	channelSubscriptionCmd.AddCommand(channelSubscriptionCreateCmd)
+
+	channelSubscriptionCreateCmd.Flags().String("channel-id", "", "Channel id")
+	channelSubscriptionCreateCmd.Flags().String("channel-name", "", "Channel name")
+	channelSubscriptionCreateCmd.Flags().String("subscriber-id", "", "Subscriber member id")
+	channelSubscriptionCreateCmd.Flags().String("subscriber-name", "", "Subscriber member name")
+	channelSubscriptionCreateCmd.Flags().String("publication-id", "", "Publication id")
+	channelSubscriptionCreateCmd.Flags().Bool("all", false, "Subscribe to every publication in the channel")
+	channelSubscriptionCreateCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -0,0 +1,62 @@
+package cmd_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kadoshita/skyway-cli/cmd"
+)
+
+func TestSubscribablePublications(t *testing.T) {
+	cases := []struct {
+		name         string
+		subscriberId string
+		expected     []string
+	}{
+		{"自分のパブリケーションは除く", "m1", []string{"p3"}},
+		{"既に購読しているパブリケーションは除く", "m2", []string{"p1", "p2"}},
+		{"転送されたパブリケーションの公開者は自分のパブリケーションだけを除く", "m3", []string{"p1", "p2"}},
+		{"チャンネルに居ないメンバーの場合は全てのパブリケーション", "m4", []string{"p1", "p2", "p3"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var actual []string
+			for _, publication := range cmd.SubscribablePublications(testChannel, c.subscriberId) {
+				actual = append(actual, publication.Id)
+			}
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+
+	t.Run("購読できるパブリケーションが無い場合は空", func(t *testing.T) {
+		channel := testChannel
+		channel.Publications = testChannel.Publications[:2]
+		if actual := cmd.SubscribablePublications(channel, "m1"); len(actual) != 0 {
+			t.Errorf("expected no publications, got %v", actual)
+		}
+	})
+}
+
+func TestValidateSubscriptionTarget(t *testing.T) {
+	cases := []struct {
+		name          string
+		publicationId string
+		all           bool
+		expectError   bool
+	}{
+		{"--publication-idだけを指定した場合はエラーにならない", "p1", false, false},
+		{"--allだけを指定した場合はエラーにならない", "", true, false},
+		{"どちらも指定しない場合はエラー", "", false, true},
+		{"両方を指定した場合はエラー", "p1", true, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := cmd.ValidateSubscriptionTarget(c.publicationId, c.all)
+			if (err != nil) != c.expectError {
+				t.Errorf("expected error: %v, got %v", c.expectError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// channelSubscriptionDeleteCmd represents the subscription delete command
+var channelSubscriptionDeleteCmd = &cobra.Command{
+	Use:   "delete <subscription-id>",
+	Short: "Unsubscribe from a publication",
+	Long: `Unsubscribe from a publication.
+The removed subscription is printed as JSON.`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		channelId, err := cmd.Flags().GetString("channel-id")
+		checkErr(err)
+
+		channelName, err := cmd.Flags().GetString("channel-name")
+		checkErr(err)
+
+		pretty, err := cmd.Flags().GetBool("pretty")
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		channel, err := findChannel(cmd.Context(), client, channelId, channelName)
+		checkErr(err)
+
+		subscription, err := findSubscription(channel, args[0])
+		checkErr(err)
+
+		err = client.UnsubscribeStream(cmd.Context(), channel.Id, subscription.Id)
+		checkErr(err)
+
+		printJSON(subscription, pretty)
+	},
+}
+
+func init() {
+	channelSubscriptionCmd.AddCommand(channelSubscriptionDeleteCmd)
+
+	channelSubscriptionDeleteCmd.Flags().String("channel-id", "", "Channel id")
+	channelSubscriptionDeleteCmd.Flags().String("channel-name", "", "Channel name")
+	channelSubscriptionDeleteCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+}
@@ -1,13 +1,18 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
-	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tidwall/sjson"
 )
 
 const tokenTempl = `{
@@ -22,70 +27,301 @@ const tokenTempl = `{
         "read"
       ],
       "turn": true,
-      "channels": [
-        {
-          "id": "%s",
-          "name": "%s",
-          "actions": [
-            "read"
-          ],
-          "members": []
-        }
-      ]
+      "channels": []
     }
   }
 }`
 
+// watchTokenTemplate returns a token template which can read the events of the channels.
+func watchTokenTemplate(channels []skyway.Channel) (string, error) {
+	tmpl := tokenTempl
+	for _, channel := range channels {
+		var err error
+		tmpl, err = sjson.Set(tmpl, "scope.app.channels.-1", map[string]interface{}{
+			"id":      channel.Id,
+			"name":    channel.Name,
+			"actions": []string{"read"},
+			"members": []interface{}{},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to set channel to token template. id: %s", channel.Id)
+		}
+	}
+	return tmpl, nil
+}
+
+// readChannelIds reads channel ids from path, one per line. Empty lines and lines starting with # are skipped.
+// When path is "-", the ids are read from stdin.
+func readChannelIds(path string) ([]string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read channel ids: %w", err)
+	}
+
+	var ids []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	return ids, nil
+}
+
 // channelWatchCmd represents the watch command
 var channelWatchCmd = &cobra.Command{
 	Use:   "watch",
 	Short: "Watch channel events",
+	Long: `Watch channel events and print them as JSON until interrupted.
+Multiple channels can be watched at once with --id, --ids-from and --name, such as --id a --id b.
+Each event is printed with the channelId of the channel, and each channel is subscribed over its own connection.
+With --record, all events are also written to the file as NDJSON with the time they were received, with the snapshots of the channels.
+The file can be replayed with "channel replay".
+With --webhook, the events selected by --events and --member are also posted to the URL, signed with HMAC-SHA256 in the X-Skyway-Cli-Signature-256 header.
+The body is the same JSON as printed with --output json: the channelEventNotification message of the RTC API with the channelId added.
+The queued events are delivered before the command exits, for up to 10 seconds.
+The secret is set by the skyway.webhook.secret configuration or the SKYWAY_WEBHOOK_SECRET environment variable.
+Failed deliveries are retried, and events are dropped with a warning when more than --webhook-queue-size events wait for delivery.
+With --output timeline, each event is printed as a line with the local time and the names of the members, such as "12:03:04 alice published video p1".
+The names are resolved with a snapshot of the channel taken before watching, and with the following events.
+With --events, only the events of the given types are printed, such as MemberAdded,StreamPublished.
+With --member, only the events concerning the member are printed. The member is given by id or by name.
+When the connection is lost, the events are subscribed again after reconnecting with backoff, and a notice is printed to stderr.
+Events which occur while reconnecting are not printed.
+The command exits with 130 when interrupted and with 124 when --timeout expires.
+The SkyWay Auth Token is renewed before it expires, and the connection is re-established with a new token when the renewal fails.`,
 	PreRun: func(cmd *cobra.Command, args []string) {
 		viper.BindPFlag("skyway.rtc_api.url", cmd.Flags().Lookup("url"))
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("channel-url"))
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		appId := viper.GetString("skyway.app_id")
 		secretKey := viper.GetString("skyway.secret_key")
 		url := viper.GetString("skyway.rtc_api.url")
+		channelUrl := viper.GetString("skyway.channel.url")
+
+		ids, err := cmd.Flags().GetStringSlice("id")
+		checkErr(err)
 
-		id, err := cmd.Flags().GetString("id")
-		cobra.CheckErr(err)
+		idsFrom, err := cmd.Flags().GetString("ids-from")
+		checkErr(err)
+		if idsFrom != "" {
+			idsInFile, err := readChannelIds(idsFrom)
+			checkErr(err)
+			ids = append(ids, idsInFile...)
+		}
 
-		name, err := cmd.Flags().GetString("name")
-		cobra.CheckErr(err)
+		names, err := cmd.Flags().GetStringSlice("name")
+		checkErr(err)
+
+		if len(ids) == 0 && len(names) == 0 {
+			checkErr(fmt.Errorf("--id, --ids-from or --name is required"))
+		}
 
 		pretty, err := cmd.Flags().GetBool("pretty")
-		cobra.CheckErr(err)
+		checkErr(err)
 
-		token, err := GenerateToken(fmt.Sprintf(tokenTempl, id, name), appId, secretKey, 3*24*60*60, []string{})
-		cobra.CheckErr(err)
+		output, err := cmd.Flags().GetString("output")
+		checkErr(err)
 
-		handleEvents := make(chan string)
-		go func() {
-			for {
-				event := <-handleEvents
+		reconnectAttempts, err := cmd.Flags().GetInt("reconnect-attempts")
+		checkErr(err)
+
+		tokenExpire, err := cmd.Flags().GetInt("token-expire")
+		checkErr(err)
+
+		eventTypes, err := cmd.Flags().GetStringSlice("events")
+		checkErr(err)
+
+		member, err := cmd.Flags().GetString("member")
+		checkErr(err)
 
-				if pretty {
-					var buffer bytes.Buffer
-					err := json.Indent(&buffer, []byte(event), "", "  ")
-					cobra.CheckErr(err)
+		record, err := cmd.Flags().GetString("record")
+		checkErr(err)
+
+		webhookUrl, err := cmd.Flags().GetString("webhook")
+		checkErr(err)
+
+		webhookQueueSize, err := cmd.Flags().GetInt("webhook-queue-size")
+		checkErr(err)
+
+		filter, err := NewEventFilter(eventTypes, member)
+		checkErr(err)
+
+		printer, err := newEventPrinter(os.Stdout, output, pretty)
+		checkErr(err)
+
+		// the snapshots resolve the ids of the members which joined before watching, and channel names to ids
+		snapshot := output == "timeline" || member != "" || record != ""
+		var channels []skyway.Channel
+		watching := map[string]bool{}
+		watch := func(channel skyway.Channel) {
+			if watching[channel.Id] {
+				return
+			}
+			watching[channel.Id] = true
+			channels = append(channels, channel)
+		}
+		client := newChannelClient(appId, secretKey, channelUrl)
+		for _, name := range names {
+			channel, err := findChannel(cmd.Context(), client, "", name)
+			checkErr(err)
+			watch(channel)
+		}
+		for _, id := range ids {
+			if watching[id] {
+				continue
+			}
+			channel := skyway.Channel{Id: id}
+			if snapshot {
+				channel, err = findChannel(cmd.Context(), client, id, "")
+				checkErr(err)
+			}
+			watch(channel)
+		}
+
+		var recorder *eventRecorder
+		if record != "" {
+			recorder, err = newEventRecorder(record)
+			checkErr(err)
+		}
+
+		channelIds := make([]string, len(channels))
+		for i, channel := range channels {
+			channelIds[i] = channel.Id
+			filter.Learn(channel)
+			printer.Learn(channel)
+			if recorder != nil {
+				checkErr(recorder.RecordSnapshot(channel, time.Now()))
+			}
+		}
 
-					fmt.Println(buffer.String())
-				} else {
-					fmt.Println(event)
+		tmpl, err := watchTokenTemplate(channels)
+		checkErr(err)
+
+		// the token is updated before it expires, so that the watch can run longer than its expiry
+		tokens := skyway.ReuseTokenSource(skyway.TokenSourceFunc(func(ctx context.Context) (string, error) {
+			return GenerateToken(tmpl, appId, secretKey, tokenExpire, []string{})
+		}), 10*time.Minute)
+		_, err = tokens.Token(cmd.Context())
+		checkErr(err)
+
+		for _, channel := range channels {
+			recordChannel(appId, channel, "watch")
+		}
+
+		var webhook *skyway.Webhook
+		if webhookUrl != "" {
+			secret := viper.GetString("skyway.webhook.secret")
+			if secret == "" {
+				checkErr(fmt.Errorf("webhook secret is required. set skyway.webhook.secret configuration or SKYWAY_WEBHOOK_SECRET environment variable"))
+			}
+			webhook = skyway.NewWebhook(webhookUrl, secret, webhookQueueSize, skyway.WithRetryPolicy(retryPolicy()))
+			webhook.OnError = func(err error) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+			}
+		}
+
+		handleEvent := func(event *skyway.Event) error {
+			// all events are recorded, so that they can be replayed with other filters
+			if recorder != nil {
+				if err := recorder.RecordEvent(event); err != nil {
+					return err
+				}
+			}
+			if !filter.Match(event) {
+				return nil
+			}
+			if webhook != nil {
+				queued, err := webhook.Send(event)
+				if err != nil {
+					return err
+				}
+				if !queued {
+					slog.Warn("Dropped webhook delivery because the queue is full", "type", event.Type, "channel", event.ChannelId)
+				}
+			}
+			return printer.Print(event)
+		}
+
+		// the stream is stopped when handling an event fails, so that the command exits after cleaning up
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		// handleErr is read after handled is closed
+		var handleErr error
+		handleEvents := make(chan *skyway.Event)
+		handled := make(chan struct{})
+		go func() {
+			defer close(handled)
+			for event := range handleEvents {
+				if handleErr != nil {
+					// the remaining events are dropped until the stream stops
+					continue
+				}
+				if err := handleEvent(event); err != nil {
+					handleErr = err
+					cancel()
 				}
 			}
 		}()
-		err = internal.SubscribeEvents(id, name, token, appId, url, handleEvents)
-		cobra.CheckErr(err)
+
+		reconnectPolicy := skyway.DefaultReconnectPolicy
+		reconnectPolicy.MaxAttempts = reconnectAttempts
+		stream := skyway.NewEventStream(url, appId, tokens, skyway.WithReconnectPolicy(reconnectPolicy))
+		stream.OnReconnect = func(err error, attempt int, wait time.Duration) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "connection lost: %v. reconnecting in %s (attempt %d)\n", err, wait.Round(time.Millisecond), attempt)
+		}
+
+		// the context is cancelled by SIGINT, SIGTERM or --timeout
+		err = stream.SubscribeChannels(ctx, channelIds, handleEvents)
+		close(handleEvents)
+		<-handled
+		if err == nil {
+			err = handleErr
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "shutting down...")
+
+		if webhook != nil {
+			// deliver the queued events before exiting
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(cmd.Context()), 10*time.Second)
+			defer cancel()
+			if err := webhook.Close(ctx); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "failed to deliver the queued webhook events: %v\n", err)
+			}
+		}
+		if recorder != nil {
+			if closeErr := recorder.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		checkErr(err)
+		exitIfDone(cmd.Context())
 	},
 }
 
 func init() {
 	channelCmd.AddCommand(channelWatchCmd)
 
-	channelWatchCmd.Flags().String("id", "", "Channel id")
-	channelWatchCmd.Flags().String("name", "", "Channel name")
+	channelWatchCmd.Flags().StringSlice("id", []string{}, "Channel id. This option can be repeated to watch multiple channels")
+	channelWatchCmd.Flags().String("ids-from", "", "File with channel ids to watch, one per line. Read from stdin when -")
+	channelWatchCmd.Flags().StringSlice("name", []string{}, "Channel name. This option can be repeated to watch multiple channels")
+	channelWatchCmd.Flags().String("output", "json", "Output format. json or timeline")
+	channelWatchCmd.Flags().StringSlice("events", []string{}, "Event types to print, such as MemberAdded,StreamPublished. All events are printed when empty")
+	channelWatchCmd.Flags().String("member", "", "Id or name of the member whose events are printed. Events of all members are printed when empty")
+	channelWatchCmd.Flags().String("record", "", "NDJSON file to record the events to, regardless of --events and --member. The file is overwritten")
+	channelWatchCmd.Flags().String("webhook", "", "URL to post the events to")
+	channelWatchCmd.Flags().Int("webhook-queue-size", 1000, "Maximum number of events waiting for delivery to the webhook")
+	channelWatchCmd.Flags().Int("token-expire", 3*24*60*60, "Expiry of the SkyWay Auth Token in seconds. The token is renewed before it expires")
+	channelWatchCmd.Flags().Int("reconnect-attempts", 0, "Maximum number of consecutive reconnect attempts when the connection is lost. Unlimited when 0")
 
 	channelWatchCmd.Flags().String("url", "wss://rtc-api.skyway.ntt.com/ws", "SkyWay RTC API URL. This option can also be set by the skyway.rtc_api.url configuration or the SKYWAY_RTC_API_URL environment variable.")
+	channelWatchCmd.Flags().String("channel-url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL to get the snapshot of the channel. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
 }
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete the channels in a channel manifest",
+	Long: `Delete the channels in a YAML manifest used by "apply".
+Channels which do not exist are ignored.
+Without --yes, a confirmation prompt is shown before deleting.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("skyway.app_id", cmd.Flags().Lookup("app-id"))
+		viper.BindPFlag("skyway.secret_key", cmd.Flags().Lookup("secret-key"))
+		viper.BindPFlag("skyway.channel.url", cmd.Flags().Lookup("url"))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		appId := viper.GetString("skyway.app_id")
+		secretKey := viper.GetString("skyway.secret_key")
+		url := viper.GetString("skyway.channel.url")
+
+		filename, err := cmd.Flags().GetString("filename")
+		checkErr(err)
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		checkErr(err)
+
+		yes, err := cmd.Flags().GetBool("yes")
+		checkErr(err)
+
+		manifest, err := internal.LoadManifest(filename)
+		checkErr(err)
+
+		client := newChannelClient(appId, secretKey, url)
+
+		var actions []internal.Action
+		for _, channelManifest := range manifest.Channels {
+			current, err := currentChannel(cmd.Context(), client, channelManifest.Name)
+			checkErr(err)
+
+			actions = append(actions, internal.PlanDelete(channelManifest, current)...)
+		}
+
+		if len(actions) == 0 {
+			fmt.Println("No changes")
+			return
+		}
+
+		for _, action := range actions {
+			fmt.Println(action)
+		}
+		if dryRun {
+			return
+		}
+
+		if !yes {
+			ok, err := Confirm(cmd.Context(), cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("Delete %d channels?", len(actions)))
+			checkErr(err)
+			if !ok {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Aborted")
+				return
+			}
+		}
+
+		for _, action := range actions {
+			checkErr(action.Run(cmd.Context(), client))
+
+			updateRegistry(func(registry *internal.Registry) {
+				registry.MarkDeleted(appId, action.Id, time.Now())
+			})
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+
+	deleteCmd.Flags().String("app-id", "", "SkyWay App ID. This option can also be set by the skyway.app_id configuration or the SKYWAY_APP_ID environment variable.")
+	deleteCmd.Flags().String("secret-key", "", "SkyWay Secret Key. This option can also be set by the skyway.secret_key configuration or the SKYWAY_SECRET_KEY environment variable.")
+	deleteCmd.Flags().String("url", "https://channel.skyway.ntt.com/v1/json-rpc", "SkyWay Channel API URL. This option can also be set by the skyway.channel.url configuration or the SKYWAY_CHANNEL_URL environment variable.")
+
+	deleteCmd.Flags().StringP("filename", "f", "", "Manifest file")
+	deleteCmd.MarkFlagRequired("filename")
+	deleteCmd.Flags().Bool("dry-run", false, "Only print the changes without applying them")
+	deleteCmd.Flags().BoolP("yes", "y", false, "Delete without confirmation")
+}
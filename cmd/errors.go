@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+// Exit codes of the CLI.
+// exitTimeout and exitInterrupted follow the conventions of timeout(1) and shells.
+const (
+	exitError = 1
+	// exitDiffError is the exit code of errors of "channel diff" and "channel exists",
+	// which use 1 for differences and for a missing channel, like git diff.
+	exitDiffError    = 2
+	exitNotFound     = 3
+	exitUnauthorized = 4
+	exitTimeout      = 124
+	exitInterrupted  = 130
+)
+
+// outputJSON is true with --error-format json.
+var outputJSON bool
+
+// errorKind classifies err for scripts.
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, skyway.ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, skyway.ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, skyway.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, skyway.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, context.Canceled):
+		return "interrupted"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	return "error"
+}
+
+// exitCode returns the exit code which tells why the command failed.
+func exitCode(err error) int {
+	switch errorKind(err) {
+	case "not_found":
+		return exitNotFound
+	case "unauthorized", "forbidden":
+		return exitUnauthorized
+	case "interrupted":
+		return exitInterrupted
+	case "timeout":
+		return exitTimeout
+	}
+	return exitError
+}
+
+// errorOutput is the JSON representation of an error.
+// rpc and problem hold the error object of the Channel API and the problem details of the Recording API.
+type errorOutput struct {
+	Error struct {
+		Kind    string           `json:"kind"`
+		Message string           `json:"message"`
+		RPC     *skyway.RPCError `json:"rpc,omitempty"`
+		Problem *skyway.APIError `json:"problem,omitempty"`
+	} `json:"error"`
+}
+
+func newErrorOutput(err error) errorOutput {
+	var output errorOutput
+	output.Error.Kind = errorKind(err)
+	output.Error.Message = err.Error()
+	errors.As(err, &output.Error.RPC)
+	errors.As(err, &output.Error.Problem)
+	return output
+}
+
+// exitIfDone exits with the exit code of the reason why ctx is done, such as exitInterrupted or exitTimeout.
+// Commands which run until they are stopped call it after cleaning up, so that scripts can tell how they stopped.
+func exitIfDone(ctx context.Context) {
+	if err := ctx.Err(); err != nil {
+		cancelTimeout()
+		os.Exit(exitCode(err))
+	}
+}
+
+// checkErr prints err and exits like cobra.CheckErr, but with the exit code of err.
+// With --error-format json, err is printed as JSON.
+func checkErr(err error) {
+	checkErrWithCode(err, exitError)
+}
+
+// checkErrWithCode is checkErr for commands which use exitError for another meaning.
+// The errors which would exit with exitError exit with errorCode instead.
+func checkErrWithCode(err error, errorCode int) {
+	if err == nil {
+		return
+	}
+	cancelTimeout()
+	code := exitCode(err)
+	if code == exitError {
+		code = errorCode
+	}
+	if outputJSON {
+		jsonString, marshalErr := json.Marshal(newErrorOutput(err))
+		if marshalErr == nil {
+			fmt.Fprintln(os.Stderr, string(jsonString))
+			os.Exit(code)
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(code)
+}
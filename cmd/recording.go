@@ -2,11 +2,45 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+func newRecordingClient(appId string, secretKey string, url string) *skyway.RecordingClient {
+	return skyway.NewRecordingClient(url, adminTokenSource(appId, secretKey), skyway.WithRetryPolicy(retryPolicy()))
+}
+
+// loadRecordingOutputServiceConfig builds the output service from the skyway.recording.output.<service> configuration.
+func loadRecordingOutputServiceConfig(config map[string]interface{}) (skyway.RecordingOutputService, error) {
+	var outputService skyway.RecordingOutputService
+
+	if v, ok := config["bucket"]; ok {
+		outputService.Bucket = v.(string)
+	}
+	if v, ok := config["access_key_id"]; ok {
+		outputService.AccessKeyId = v.(string)
+	}
+	if v, ok := config["secret_access_key"]; ok {
+		outputService.SecretAccessKey = v.(string)
+	}
+	if v, ok := config["region"]; ok {
+		outputService.Region = v.(string)
+	}
+	if v, ok := config["credential_file"]; ok {
+		data, err := os.ReadFile(v.(string))
+		if err != nil {
+			return outputService, err
+		}
+
+		outputService.Credential = string(data)
+	}
+
+	return outputService, nil
+}
+
 // recordingCmd represents the recording command
 var recordingCmd = &cobra.Command{
 	Use:   "recording",
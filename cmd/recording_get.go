@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/kadoshita/skyway-cli/internal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -19,28 +18,27 @@ var recordingGetCmd = &cobra.Command{
 		url := viper.GetString("skyway.recording.url")
 
 		channelId, err := cmd.Flags().GetString("channel-id")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		sessionId, err := cmd.Flags().GetString("session-id")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		pretty, err := cmd.Flags().GetBool("pretty")
-		cobra.CheckErr(err)
+		checkErr(err)
 
-		token, err := GenerateAdminToken(appId, secretKey, 3600, []string{})
-		cobra.CheckErr(err)
+		client := newRecordingClient(appId, secretKey, url)
 
-		response, err := internal.GetSession(channelId, sessionId, token, url)
-		cobra.CheckErr(err)
+		response, err := client.GetSession(cmd.Context(), channelId, sessionId)
+		checkErr(err)
 
 		if pretty {
 			jsonString, err := json.MarshalIndent(response, "", "  ")
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		} else {
 			jsonString, err := json.Marshal(response)
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kadoshita/skyway-cli/internal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -29,45 +28,44 @@ This command cannot specify multiple publication IDs yet.`,
 		url := viper.GetString("skyway.recording.url")
 
 		channelId, err := cmd.Flags().GetString("channel-id")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		pretty, err := cmd.Flags().GetBool("pretty")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		publicationId, err := cmd.Flags().GetString("publication-id")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		contentType, err := cmd.Flags().GetString("content-type")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		outputServiceName, err := cmd.Flags().GetString("output-service")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		outputServiceConfigKey := "skyway.recording.output." + outputServiceName
 		if !viper.IsSet(outputServiceConfigKey) {
-			cobra.CheckErr(fmt.Errorf("output service %s is not configured", outputServiceName))
+			checkErr(fmt.Errorf("output service %s is not configured", outputServiceName))
 		}
 		outputServiceConfig := viper.Get("skyway.recording.output." + outputServiceName).(map[string]interface{})
 
-		outputService, err := internal.LoadRecordingOutputServiceConfig(outputServiceConfig)
-		cobra.CheckErr(err)
+		outputService, err := loadRecordingOutputServiceConfig(outputServiceConfig)
+		checkErr(err)
 
 		outputService.Service = strings.ToUpper(optionToService[outputServiceName])
 
-		token, err := GenerateAdminToken(appId, secretKey, 3600, []string{})
-		cobra.CheckErr(err)
+		client := newRecordingClient(appId, secretKey, url)
 
-		response, err := internal.CreateSession(channelId, publicationId, contentType, outputService, token, url)
-		cobra.CheckErr(err)
+		response, err := client.CreateSession(cmd.Context(), channelId, publicationId, contentType, outputService)
+		checkErr(err)
 
 		if pretty {
 			jsonString, err := json.MarshalIndent(response, "", "  ")
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		} else {
 			jsonString, err := json.Marshal(response)
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(string(jsonString))
 		}
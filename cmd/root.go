@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
 	"github.com/spf13/cobra"
 	"github.com/spf13/cobra/doc"
 	"github.com/spf13/viper"
@@ -12,6 +18,21 @@ import (
 
 var cfgFile string
 
+var timeout time.Duration
+
+// cancelTimeout releases the timer of --timeout.
+var cancelTimeout context.CancelFunc = func() {}
+
+// retryPolicy returns the retry policy of the API clients configured by skyway.retry.
+func retryPolicy() skyway.RetryPolicy {
+	return skyway.RetryPolicy{
+		MaxAttempts:    viper.GetInt("skyway.retry.max_attempts"),
+		InitialBackoff: viper.GetDuration("skyway.retry.initial_backoff"),
+		MaxBackoff:     viper.GetDuration("skyway.retry.max_backoff"),
+		Jitter:         viper.GetFloat64("skyway.retry.jitter"),
+	}
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "skyway-cli",
 	Short: "A CLI tool for SkyWay developers",
@@ -20,6 +41,22 @@ By using this tool, you can automate and streamline the development process invo
 Its main features include easy API calls, and token generate.
 For engineers developing applications with SkyWay, this tool contributes to project efficiency and quality improvement.`,
 	Version: "0.0.1",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		errorFormat, err := cmd.Flags().GetString("error-format")
+		checkErr(err)
+		if errorFormat != "text" && errorFormat != "json" {
+			checkErr(fmt.Errorf("--error-format should be text or json. value: %s", errorFormat))
+		}
+		outputJSON = errorFormat == "json"
+		if jitter := viper.GetFloat64("skyway.retry.jitter"); jitter < 0 || jitter > 1 {
+			checkErr(fmt.Errorf("skyway.retry.jitter should be between 0 and 1. value: %v", jitter))
+		}
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		}
+	},
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
@@ -36,17 +73,39 @@ func GenDocs() error {
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The context of the commands is cancelled by SIGINT or SIGTERM, and a second signal terminates the process immediately.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	cancelTimeout()
 	if err != nil {
-		os.Exit(1)
+		// usage errors of channel diff and channel exists exit with exitDiffError too, as they use 1 for another meaning
+		if cmd == channelDiffCmd || cmd == channelExistsCmd {
+			os.Exit(exitDiffError)
+		}
+		os.Exit(exitError)
 	}
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	// the persistent hooks of the parent commands run too, so that --timeout applies to every command
+	cobra.EnableTraverseRunHooks = true
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.skyway-cli.yaml)")
+	rootCmd.PersistentFlags().String("error-format", "text", "Format of errors printed to stderr: text or json")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, fmt.Sprintf("Timeout of the whole command, e.g. 30s. When 0, the command has no timeout, but each API request times out after %s", skyway.DefaultHTTPTimeout))
+	rootCmd.PersistentFlags().Int("max-attempts", skyway.DefaultRetryPolicy.MaxAttempts, "Maximum number of attempts of an API request, including retries on network errors, 429 and 5xx. This option can also be set by the skyway.retry.max_attempts configuration or the SKYWAY_RETRY_MAX_ATTEMPTS environment variable.")
+	viper.BindPFlag("skyway.retry.max_attempts", rootCmd.PersistentFlags().Lookup("max-attempts"))
+	rootCmd.PersistentFlags().Float64("retry-jitter", skyway.DefaultRetryPolicy.Jitter, "Fraction of the wait between retries which is randomized, between 0 and 1. This option can also be set by the skyway.retry.jitter configuration or the SKYWAY_RETRY_JITTER environment variable.")
+	viper.BindPFlag("skyway.retry.jitter", rootCmd.PersistentFlags().Lookup("retry-jitter"))
+	viper.SetDefault("skyway.retry.initial_backoff", skyway.DefaultRetryPolicy.InitialBackoff)
+	viper.SetDefault("skyway.retry.max_backoff", skyway.DefaultRetryPolicy.MaxBackoff)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -57,7 +116,7 @@ func initConfig() {
 	} else {
 		// Find home directory.
 		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		// Search config in home directory with name ".skyway" (without extension).
 		viper.AddConfigPath(home)
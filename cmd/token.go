@@ -167,18 +167,18 @@ This command cannot append additional scopes to token template configuration yet
 		expire := viper.GetInt("skyway.token.expire")
 
 		isAdmin, err := cmd.Flags().GetBool("admin")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		if isAdmin {
 			tokenString, err := GenerateAdminToken(appId, secretKey, expire, []string{})
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(tokenString)
 		} else {
 			tokenTmpl := viper.GetString("skyway.token.tmpl")
 
 			tokenString, err := GenerateToken(tokenTmpl, appId, secretKey, expire, []string{})
-			cobra.CheckErr(err)
+			checkErr(err)
 
 			fmt.Println(tokenString)
 		}
@@ -34,29 +34,29 @@ var tokenDecodeCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		reader := bufio.NewReader(cmd.InOrStdin())
 		stdinBytes, err := reader.ReadBytes('\n')
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		isAdmin, err := cmd.Flags().GetBool("admin")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		pretty, err := cmd.Flags().GetBool("pretty")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		if isAdmin {
 			decoded, err := DecodeAdminToken(string(stdinBytes))
 			// Errors are ignored because the token is only decoded, not verified.
 			if err != nil && !errors.Is(err, jwt.ErrSignatureInvalid) {
-				cobra.CheckErr(err)
+				checkErr(err)
 			}
 
 			if pretty {
 				token, err := json.MarshalIndent(decoded, "", "  ")
-				cobra.CheckErr(err)
+				checkErr(err)
 
 				fmt.Println(string(token))
 			} else {
 				token, err := json.Marshal(decoded)
-				cobra.CheckErr(err)
+				checkErr(err)
 
 				fmt.Println(string(token))
 			}
@@ -65,17 +65,17 @@ var tokenDecodeCmd = &cobra.Command{
 			decoded, err := DecodeToken(string(stdinBytes))
 			// Errors are ignored because the token is only decoded, not verified.
 			if err != nil && !errors.Is(err, jwt.ErrSignatureInvalid) {
-				cobra.CheckErr(err)
+				checkErr(err)
 			}
 
 			if pretty {
 				token, err := json.MarshalIndent(decoded, "", "  ")
-				cobra.CheckErr(err)
+				checkErr(err)
 
 				fmt.Println(string(token))
 			} else {
 				token, err := json.Marshal(decoded)
-				cobra.CheckErr(err)
+				checkErr(err)
 
 				fmt.Println(string(token))
 			}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/labstack/echo/v4"
@@ -26,7 +27,7 @@ var tokenServeCmd = &cobra.Command{
 		expire := viper.GetInt("skyway.token.expire")
 		tokenTmpl := viper.GetString("skyway.token.tmpl")
 		port, err := cmd.Flags().GetInt("port")
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		server := echo.New()
 		server.HideBanner = true
@@ -39,7 +40,12 @@ var tokenServeCmd = &cobra.Command{
 			}
 			return c.String(200, tokenString)
 		})
+		go func() {
+			<-cmd.Context().Done()
+			server.Shutdown(context.Background())
+		}()
 		server.Start(":" + fmt.Sprint(port))
+		exitIfDone(cmd.Context())
 	},
 }
 
@@ -133,12 +133,12 @@ var tokenVerifyCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		reader := bufio.NewReader(cmd.InOrStdin())
 		stdinBytes, err := reader.ReadBytes('\n')
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		secretKey := viper.GetString("skyway.secret_key")
 
 		err = VerifyToken(string(stdinBytes), secretKey)
-		cobra.CheckErr(err)
+		checkErr(err)
 
 		fmt.Println("Token is valid")
 	},
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeUpdated ChangeType = "updated"
+)
+
+// Change is a semantic difference between two states of a channel.
+type Change struct {
+	Type ChangeType `json:"type"`
+	// Kind is channel, member, publication or subscription.
+	Kind  string      `json:"kind"`
+	Id    string      `json:"id"`
+	Field string      `json:"field,omitempty"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+	// Description is a human-readable summary of the resource, with ids resolved to member names.
+	Description string `json:"description"`
+}
+
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s %s", c.Kind, c.Description)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s %s", c.Kind, c.Description)
+	default:
+		return fmt.Sprintf("~ %s %s %s: %#v -> %#v", c.Kind, c.Description, c.Field, c.Old, c.New)
+	}
+}
+
+type channelNames struct {
+	members      map[string]skyway.Member
+	publications map[string]skyway.Publication
+}
+
+func newChannelNames(channels ...skyway.Channel) channelNames {
+	names := channelNames{members: map[string]skyway.Member{}, publications: map[string]skyway.Publication{}}
+	for _, channel := range channels {
+		for _, member := range channel.Members {
+			names.members[member.Id] = member
+		}
+		for _, publication := range channel.Publications {
+			names.publications[publication.Id] = publication
+		}
+	}
+	return names
+}
+
+func (n channelNames) member(id string) string {
+	if member, ok := n.members[id]; ok && member.Name != "" {
+		return fmt.Sprintf("%s (%s)", member.Name, id)
+	}
+	return id
+}
+
+func (n channelNames) publication(publication skyway.Publication) string {
+	return fmt.Sprintf("%s %s of %s", publication.ContentType, publication.Id, n.member(publication.PublisherId))
+}
+
+func (n channelNames) subscription(subscription skyway.Subscription) string {
+	publication := subscription.PublicationId
+	if p, ok := n.publications[subscription.PublicationId]; ok {
+		publication = n.publication(p)
+	}
+	return fmt.Sprintf("%s: %s subscribes %s", subscription.Id, n.member(subscription.SubscriberId), publication)
+}
+
+// DiffChannels returns the members, publications and subscriptions added or removed from a to b,
+// and the changes of metadata and isEnabled.
+func DiffChannels(a skyway.Channel, b skyway.Channel) []Change {
+	// names are resolved from both states so that removed members are still shown by name
+	names := newChannelNames(b, a)
+	var changes []Change
+
+	if a.Id != b.Id {
+		changes = append(changes, Change{Type: ChangeUpdated, Kind: "channel", Id: b.Id, Field: "id", Old: a.Id, New: b.Id, Description: b.Name})
+	}
+	if a.Name != b.Name {
+		changes = append(changes, Change{Type: ChangeUpdated, Kind: "channel", Id: b.Id, Field: "name", Old: a.Name, New: b.Name, Description: b.Name})
+	}
+	if a.Metadata != b.Metadata {
+		changes = append(changes, Change{Type: ChangeUpdated, Kind: "channel", Id: b.Id, Field: "metadata", Old: a.Metadata, New: b.Metadata, Description: b.Name})
+	}
+
+	oldMembers := map[string]skyway.Member{}
+	for _, member := range a.Members {
+		oldMembers[member.Id] = member
+	}
+	newMembers := map[string]bool{}
+	for _, member := range b.Members {
+		newMembers[member.Id] = true
+		old, ok := oldMembers[member.Id]
+		if !ok {
+			changes = append(changes, Change{Type: ChangeAdded, Kind: "member", Id: member.Id, Description: names.member(member.Id)})
+			continue
+		}
+		if old.Name != member.Name {
+			changes = append(changes, Change{Type: ChangeUpdated, Kind: "member", Id: member.Id, Field: "name", Old: old.Name, New: member.Name, Description: names.member(member.Id)})
+		}
+		if old.Metadata != member.Metadata {
+			changes = append(changes, Change{Type: ChangeUpdated, Kind: "member", Id: member.Id, Field: "metadata", Old: old.Metadata, New: member.Metadata, Description: names.member(member.Id)})
+		}
+	}
+	for _, member := range a.Members {
+		if !newMembers[member.Id] {
+			changes = append(changes, Change{Type: ChangeRemoved, Kind: "member", Id: member.Id, Description: names.member(member.Id)})
+		}
+	}
+
+	oldPublications := map[string]skyway.Publication{}
+	for _, publication := range a.Publications {
+		oldPublications[publication.Id] = publication
+	}
+	newPublications := map[string]bool{}
+	for _, publication := range b.Publications {
+		newPublications[publication.Id] = true
+		old, ok := oldPublications[publication.Id]
+		if !ok {
+			changes = append(changes, Change{Type: ChangeAdded, Kind: "publication", Id: publication.Id, Description: names.publication(publication)})
+			continue
+		}
+		if old.IsEnabled != publication.IsEnabled {
+			changes = append(changes, Change{Type: ChangeUpdated, Kind: "publication", Id: publication.Id, Field: "isEnabled", Old: old.IsEnabled, New: publication.IsEnabled, Description: names.publication(publication)})
+		}
+		if old.Metadata != publication.Metadata {
+			changes = append(changes, Change{Type: ChangeUpdated, Kind: "publication", Id: publication.Id, Field: "metadata", Old: old.Metadata, New: publication.Metadata, Description: names.publication(publication)})
+		}
+	}
+	for _, publication := range a.Publications {
+		if !newPublications[publication.Id] {
+			changes = append(changes, Change{Type: ChangeRemoved, Kind: "publication", Id: publication.Id, Description: names.publication(publication)})
+		}
+	}
+
+	oldSubscriptions := map[string]bool{}
+	for _, subscription := range a.Subscriptions {
+		oldSubscriptions[subscription.Id] = true
+	}
+	newSubscriptions := map[string]bool{}
+	for _, subscription := range b.Subscriptions {
+		newSubscriptions[subscription.Id] = true
+		if !oldSubscriptions[subscription.Id] {
+			changes = append(changes, Change{Type: ChangeAdded, Kind: "subscription", Id: subscription.Id, Description: names.subscription(subscription)})
+		}
+	}
+	for _, subscription := range a.Subscriptions {
+		if !newSubscriptions[subscription.Id] {
+			changes = append(changes, Change{Type: ChangeRemoved, Kind: "subscription", Id: subscription.Id, Description: names.subscription(subscription)})
+		}
+	}
+
+	return changes
+}
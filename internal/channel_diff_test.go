@@ -0,0 +1,73 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+func TestDiffChannels(t *testing.T) {
+	before := skyway.Channel{
+		Id:       "c1",
+		Name:     "room",
+		Metadata: "old",
+		Members: []skyway.Member{
+			{Id: "m1", Name: "alice"},
+			{Id: "m2", Name: "bob"},
+		},
+		Publications: []skyway.Publication{
+			{Id: "p1", PublisherId: "m1", ContentType: "video", IsEnabled: true},
+			{Id: "p2", PublisherId: "m2", ContentType: "audio", IsEnabled: true},
+		},
+		Subscriptions: []skyway.Subscription{
+			{Id: "s1", PublicationId: "p2", SubscriberId: "m1"},
+		},
+	}
+
+	t.Run("同じ状態の場合は差分が無い", func(t *testing.T) {
+		changes := internal.DiffChannels(before, before)
+
+		if len(changes) != 0 {
+			t.Errorf("expected no changes, got %v", changes)
+		}
+	})
+	t.Run("追加、削除、更新を検出する", func(t *testing.T) {
+		after := skyway.Channel{
+			Id:       "c1",
+			Name:     "room",
+			Metadata: "new",
+			Members: []skyway.Member{
+				{Id: "m1", Name: "alice", Metadata: "host"},
+				{Id: "m3", Name: "carol"},
+			},
+			Publications: []skyway.Publication{
+				{Id: "p1", PublisherId: "m1", ContentType: "video", IsEnabled: false},
+			},
+			Subscriptions: []skyway.Subscription{
+				{Id: "s2", PublicationId: "p1", SubscriberId: "m3"},
+			},
+		}
+
+		changes := internal.DiffChannels(before, after)
+
+		expected := []string{
+			`~ channel room metadata: "old" -> "new"`,
+			`~ member alice (m1) metadata: "" -> "host"`,
+			`+ member carol (m3)`,
+			`- member bob (m2)`,
+			`~ publication video p1 of alice (m1) isEnabled: true -> false`,
+			`- publication audio p2 of bob (m2)`,
+			`+ subscription s2: carol (m3) subscribes video p1 of alice (m1)`,
+			`- subscription s1: alice (m1) subscribes audio p2 of bob (m2)`,
+		}
+		if len(changes) != len(expected) {
+			t.Fatalf("expected %d changes, got %v", len(expected), changes)
+		}
+		for i := range expected {
+			if changes[i].String() != expected[i] {
+				t.Errorf("expected %s, got %s", expected[i], changes[i].String())
+			}
+		}
+	})
+}
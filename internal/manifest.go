@@ -0,0 +1,453 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+	"gopkg.in/yaml.v3"
+)
+
+type Manifest struct {
+	Channels []ChannelManifest `yaml:"channels"`
+}
+
+type ChannelManifest struct {
+	Name     string           `yaml:"name"`
+	Metadata string           `yaml:"metadata"`
+	Members  []MemberManifest `yaml:"members"`
+}
+
+// MemberManifest describes a member of a channel.
+// The type and subtype of a member cannot be updated, so changing them replaces the member.
+// They default to person, and are only compared with an existing member when they are set.
+type MemberManifest struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Subtype  string `yaml:"subtype"`
+	Metadata string `yaml:"metadata"`
+	// Ttl is the member TTL in seconds from the time of apply. The member does not expire when 0.
+	// The TTL of an existing member is renewed when it differs by more than ttlTolerance,
+	// and a member which expires is replaced when Ttl is 0, because the TTL cannot be removed.
+	Ttl          int                   `yaml:"ttl"`
+	Publications []PublicationManifest `yaml:"publications"`
+}
+
+// PublicationManifest describes a publication of a member.
+// A publication is identified by its content type and metadata, so changing either of them replaces the publication.
+type PublicationManifest struct {
+	ContentType string `yaml:"contentType"`
+	Metadata    string `yaml:"metadata"`
+	Enabled     *bool  `yaml:"enabled"`
+}
+
+func LoadManifest(path string) (Manifest, error) {
+	var manifest Manifest
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("invalid manifest. file: %s err: %v", path, err)
+	}
+
+	names := map[string]bool{}
+	for i, channel := range manifest.Channels {
+		if channel.Name == "" {
+			return manifest, fmt.Errorf("channels[%d].name is required", i)
+		}
+		if names[channel.Name] {
+			return manifest, fmt.Errorf("channel name should be unique. name: %s", channel.Name)
+		}
+		names[channel.Name] = true
+
+		memberNames := map[string]bool{}
+		for j, member := range channel.Members {
+			if member.Name == "" {
+				return manifest, fmt.Errorf("channels[%d].members[%d].name is required", i, j)
+			}
+			if memberNames[member.Name] {
+				return manifest, fmt.Errorf("member name should be unique in a channel. channel: %s name: %s", channel.Name, member.Name)
+			}
+			memberNames[member.Name] = true
+
+			for k, publication := range member.Publications {
+				if publication.ContentType != "audio" && publication.ContentType != "video" && publication.ContentType != "data" {
+					return manifest, fmt.Errorf("channels[%d].members[%d].publications[%d].contentType should be audio, video or data. value: %s", i, j, k, publication.ContentType)
+				}
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+type ActionType string
+
+const (
+	ActionCreate ActionType = "create"
+	ActionUpdate ActionType = "update"
+	ActionDelete ActionType = "delete"
+)
+
+// Action is a single change to bring a channel in line with its manifest.
+type Action struct {
+	Type ActionType
+	// Kind is channel, member or publication.
+	Kind string
+	// Target is a human-readable path of the resource, e.g. "room/alice".
+	Target string
+	// Id is the id of the existing resource. It is empty for create actions.
+	Id     string
+	Detail string
+	run    func(ctx context.Context, client *skyway.ChannelClient) error
+}
+
+func (a Action) String() string {
+	var symbol string
+	switch a.Type {
+	case ActionCreate:
+		symbol = "+"
+	case ActionUpdate:
+		symbol = "~"
+	case ActionDelete:
+		symbol = "-"
+	}
+	if a.Detail == "" {
+		return fmt.Sprintf("%s %s %s", symbol, a.Kind, a.Target)
+	}
+	return fmt.Sprintf("%s %s %s (%s)", symbol, a.Kind, a.Target, a.Detail)
+}
+
+func (a Action) Run(ctx context.Context, client *skyway.ChannelClient) error {
+	return a.run(ctx, client)
+}
+
+// applyState holds the ids resolved while running the actions of a channel,
+// because resources created by earlier actions are referenced by later ones.
+type applyState struct {
+	channelId string
+	memberIds map[string]string
+}
+
+func publicationKey(contentType string, metadata string) string {
+	return contentType + "\x00" + metadata
+}
+
+func publicationTarget(memberTarget string, contentType string, metadata string) string {
+	if metadata == "" {
+		return memberTarget + "/" + contentType
+	}
+	return fmt.Sprintf("%s/%s[%s]", memberTarget, contentType, metadata)
+}
+
+// ttlTolerance is how much the TTL of an existing member may differ from the manifest without being renewed.
+const ttlTolerance = time.Minute
+
+// replaceReason returns why the existing member should be replaced to match the manifest, or "" when it can be kept.
+func replaceReason(member MemberManifest, current skyway.Member) string {
+	switch {
+	case member.Type != "" && member.Type != current.Type:
+		return "type"
+	case member.Subtype != "" && member.Subtype != current.Subtype:
+		return "subtype"
+	case member.Ttl == 0 && current.TtlSec != nil:
+		return "ttl"
+	}
+	return ""
+}
+
+// ttlChanged reports whether the TTL of the existing member differs from the manifest applied at now.
+func ttlChanged(member MemberManifest, current skyway.Member, now time.Time) bool {
+	if member.Ttl == 0 {
+		return false
+	}
+	if current.TtlSec == nil {
+		return true
+	}
+	diff := time.Unix(*current.TtlSec, 0).Sub(now.Add(time.Duration(member.Ttl) * time.Second))
+	return diff > ttlTolerance || diff < -ttlTolerance
+}
+
+func isEnabled(publication PublicationManifest) bool {
+	return publication.Enabled == nil || *publication.Enabled
+}
+
+func defaultString(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// PlanApply returns the actions to bring current in line with manifest.
+// current is nil when the channel does not exist.
+// When prune is true, members and publications which are not in the manifest are removed.
+// Members without a name are never removed, because the manifest cannot refer to them.
+// When members share a name, the first one is applied, and the others are removed with prune.
+func PlanApply(manifest ChannelManifest, current *skyway.Channel, prune bool) []Action {
+	now := time.Now()
+	state := &applyState{memberIds: map[string]string{}}
+	var actions []Action
+
+	if current == nil {
+		actions = append(actions, Action{
+			Type:   ActionCreate,
+			Kind:   "channel",
+			Target: manifest.Name,
+			run: func(ctx context.Context, client *skyway.ChannelClient) error {
+				channel, err := client.CreateChannel(ctx, manifest.Name, manifest.Metadata)
+				if err != nil {
+					return err
+				}
+				state.channelId = channel.Id
+				return nil
+			},
+		})
+		current = &skyway.Channel{Name: manifest.Name, Metadata: manifest.Metadata}
+	} else {
+		state.channelId = current.Id
+		// the first member with a name is the one the manifest refers to, as the plan shows
+		for _, member := range current.Members {
+			if _, ok := state.memberIds[member.Name]; !ok {
+				state.memberIds[member.Name] = member.Id
+			}
+		}
+
+		if current.Metadata != manifest.Metadata {
+			actions = append(actions, Action{
+				Type:   ActionUpdate,
+				Kind:   "channel",
+				Target: manifest.Name,
+				Id:     current.Id,
+				Detail: "metadata",
+				run: func(ctx context.Context, client *skyway.ChannelClient) error {
+					return client.UpdateChannelMetadata(ctx, state.channelId, manifest.Metadata)
+				},
+			})
+		}
+	}
+
+	desiredMembers := map[string]bool{}
+	for _, member := range manifest.Members {
+		member := member
+		desiredMembers[member.Name] = true
+		target := manifest.Name + "/" + member.Name
+
+		var currentMember *skyway.Member
+		for i := range current.Members {
+			if current.Members[i].Name == member.Name {
+				currentMember = &current.Members[i]
+				break
+			}
+		}
+
+		if currentMember != nil {
+			if reason := replaceReason(member, *currentMember); reason != "" {
+				id := currentMember.Id
+				actions = append(actions, Action{
+					Type:   ActionDelete,
+					Kind:   "member",
+					Target: target,
+					Id:     id,
+					Detail: "replace to change " + reason,
+					run: func(ctx context.Context, client *skyway.ChannelClient) error {
+						return client.LeaveChannel(ctx, state.channelId, id)
+					},
+				})
+				currentMember = nil
+			}
+		}
+
+		if currentMember == nil {
+			actions = append(actions, Action{
+				Type:   ActionCreate,
+				Kind:   "member",
+				Target: target,
+				run: func(ctx context.Context, client *skyway.ChannelClient) error {
+					params := skyway.AddMemberParams{
+						ChannelId: state.channelId,
+						Name:      member.Name,
+						Type:      defaultString(member.Type, "person"),
+						Subtype:   defaultString(member.Subtype, "person"),
+						Metadata:  member.Metadata,
+					}
+					if member.Ttl > 0 {
+						params.TtlSec = time.Now().Add(time.Duration(member.Ttl) * time.Second).Unix()
+					}
+					memberId, err := client.AddMember(ctx, params)
+					if err != nil {
+						return err
+					}
+					state.memberIds[member.Name] = memberId
+					return nil
+				},
+			})
+		} else {
+			id := currentMember.Id
+			if currentMember.Metadata != member.Metadata {
+				actions = append(actions, Action{
+					Type:   ActionUpdate,
+					Kind:   "member",
+					Target: target,
+					Id:     currentMember.Id,
+					Detail: "metadata",
+					run: func(ctx context.Context, client *skyway.ChannelClient) error {
+						return client.UpdateMemberMetadata(ctx, state.channelId, id, member.Metadata)
+					},
+				})
+			}
+			if ttlChanged(member, *currentMember, now) {
+				actions = append(actions, Action{
+					Type:   ActionUpdate,
+					Kind:   "member",
+					Target: target,
+					Id:     currentMember.Id,
+					Detail: "ttl",
+					run: func(ctx context.Context, client *skyway.ChannelClient) error {
+						ttlSec := time.Now().Add(time.Duration(member.Ttl) * time.Second).Unix()
+						return client.UpdateMemberTtl(ctx, state.channelId, id, ttlSec)
+					},
+				})
+			}
+		}
+
+		// current publications of the member, grouped by their identity
+		currentPublications := map[string][]skyway.Publication{}
+		if currentMember != nil {
+			for _, publication := range current.Publications {
+				if publication.PublisherId == currentMember.Id {
+					key := publicationKey(publication.ContentType, publication.Metadata)
+					currentPublications[key] = append(currentPublications[key], publication)
+				}
+			}
+		}
+
+		matchedPublications := map[string]bool{}
+		for _, publication := range member.Publications {
+			publication := publication
+			publicationTarget := publicationTarget(target, publication.ContentType, publication.Metadata)
+
+			key := publicationKey(publication.ContentType, publication.Metadata)
+			if candidates := currentPublications[key]; len(candidates) > 0 {
+				matched := candidates[0]
+				currentPublications[key] = candidates[1:]
+				matchedPublications[matched.Id] = true
+
+				if matched.IsEnabled != isEnabled(publication) {
+					detail := "enable"
+					if !isEnabled(publication) {
+						detail = "disable"
+					}
+					actions = append(actions, Action{
+						Type:   ActionUpdate,
+						Kind:   "publication",
+						Target: publicationTarget,
+						Id:     matched.Id,
+						Detail: detail,
+						run: func(ctx context.Context, client *skyway.ChannelClient) error {
+							if isEnabled(publication) {
+								return client.EnablePublication(ctx, state.channelId, matched.Id)
+							}
+							return client.DisablePublication(ctx, state.channelId, matched.Id)
+						},
+					})
+				}
+				continue
+			}
+
+			actions = append(actions, Action{
+				Type:   ActionCreate,
+				Kind:   "publication",
+				Target: publicationTarget,
+				run: func(ctx context.Context, client *skyway.ChannelClient) error {
+					params := skyway.PublishStreamParams{
+						ChannelId:   state.channelId,
+						PublisherId: state.memberIds[member.Name],
+						ContentType: publication.ContentType,
+						Metadata:    publication.Metadata,
+					}
+					if !isEnabled(publication) {
+						enabled := false
+						params.IsEnabled = &enabled
+					}
+					_, err := client.PublishStream(ctx, params)
+					return err
+				},
+			})
+		}
+
+		if prune && currentMember != nil {
+			for _, publication := range current.Publications {
+				publication := publication
+				if publication.PublisherId != currentMember.Id || matchedPublications[publication.Id] {
+					continue
+				}
+				actions = append(actions, Action{
+					Type:   ActionDelete,
+					Kind:   "publication",
+					Target: publicationTarget(target, publication.ContentType, publication.Metadata),
+					Id:     publication.Id,
+					Detail: publication.Id,
+					run: func(ctx context.Context, client *skyway.ChannelClient) error {
+						return client.UnpublishStream(ctx, state.channelId, publication.Id)
+					},
+				})
+			}
+		}
+	}
+
+	if prune {
+		first := map[string]string{}
+		for _, member := range current.Members {
+			member := member
+			if member.Name == "" {
+				continue
+			}
+			detail := member.Id
+			if desiredMembers[member.Name] {
+				// the members after the first one with a name in the manifest are duplicates which the manifest cannot refer to
+				if _, ok := first[member.Name]; !ok {
+					first[member.Name] = member.Id
+					continue
+				}
+				detail = member.Id + ", duplicate of " + first[member.Name]
+			}
+			actions = append(actions, Action{
+				Type:   ActionDelete,
+				Kind:   "member",
+				Target: manifest.Name + "/" + member.Name,
+				Id:     member.Id,
+				Detail: detail,
+				run: func(ctx context.Context, client *skyway.ChannelClient) error {
+					return client.LeaveChannel(ctx, state.channelId, member.Id)
+				},
+			})
+		}
+	}
+
+	return actions
+}
+
+// PlanDelete returns the actions to delete the channel of manifest.
+// current is nil when the channel does not exist.
+func PlanDelete(manifest ChannelManifest, current *skyway.Channel) []Action {
+	if current == nil {
+		return nil
+	}
+	return []Action{
+		{
+			Type:   ActionDelete,
+			Kind:   "channel",
+			Target: manifest.Name,
+			Id:     current.Id,
+			Detail: current.Id,
+			run: func(ctx context.Context, client *skyway.ChannelClient) error {
+				return client.DeleteChannel(ctx, current.Id)
+			},
+		},
+	}
+}
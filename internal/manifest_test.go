@@ -0,0 +1,216 @@
+package internal_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+func planStrings(actions []internal.Action) []string {
+	var result []string
+	for _, action := range actions {
+		result = append(result, action.String())
+	}
+	return result
+}
+
+func assertPlan(t *testing.T, actions []internal.Action, expected []string) {
+	t.Helper()
+	actual := planStrings(actions)
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, actual)
+			return
+		}
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	disabled := false
+	manifest := internal.ChannelManifest{
+		Name:     "room",
+		Metadata: "meta",
+		Members: []internal.MemberManifest{
+			{
+				Name: "alice",
+				Publications: []internal.PublicationManifest{
+					{ContentType: "video"},
+					{ContentType: "audio", Enabled: &disabled},
+				},
+			},
+		},
+	}
+
+	t.Run("チャンネルが存在しない場合は全て作成する", func(t *testing.T) {
+		actions := internal.PlanApply(manifest, nil, false)
+
+		assertPlan(t, actions, []string{
+			"+ channel room",
+			"+ member room/alice",
+			"+ publication room/alice/video",
+			"+ publication room/alice/audio",
+		})
+	})
+	t.Run("状態が一致している場合は変更しない", func(t *testing.T) {
+		current := &skyway.Channel{
+			Id:       "c1",
+			Name:     "room",
+			Metadata: "meta",
+			Members:  []skyway.Member{{Id: "m1", Name: "alice"}},
+			Publications: []skyway.Publication{
+				{Id: "p1", PublisherId: "m1", ContentType: "video", IsEnabled: true},
+				{Id: "p2", PublisherId: "m1", ContentType: "audio", IsEnabled: false},
+			},
+		}
+
+		actions := internal.PlanApply(manifest, current, true)
+
+		assertPlan(t, actions, []string{})
+	})
+	t.Run("差分がある場合は更新する", func(t *testing.T) {
+		current := &skyway.Channel{
+			Id:       "c1",
+			Name:     "room",
+			Metadata: "old",
+			Members:  []skyway.Member{{Id: "m1", Name: "alice", Metadata: "old"}},
+			Publications: []skyway.Publication{
+				{Id: "p1", PublisherId: "m1", ContentType: "video", IsEnabled: false},
+			},
+		}
+
+		actions := internal.PlanApply(manifest, current, false)
+
+		assertPlan(t, actions, []string{
+			"~ channel room (metadata)",
+			"~ member room/alice (metadata)",
+			"~ publication room/alice/video (enable)",
+			"+ publication room/alice/audio",
+		})
+	})
+	t.Run("pruneの場合はマニフェストに無いリソースを削除する", func(t *testing.T) {
+		current := &skyway.Channel{
+			Id:       "c1",
+			Name:     "room",
+			Metadata: "meta",
+			Members:  []skyway.Member{{Id: "m1", Name: "alice"}, {Id: "m2", Name: "bob"}},
+			Publications: []skyway.Publication{
+				{Id: "p1", PublisherId: "m1", ContentType: "video", IsEnabled: true},
+				{Id: "p2", PublisherId: "m1", ContentType: "audio", IsEnabled: false},
+				{Id: "p3", PublisherId: "m1", ContentType: "data", IsEnabled: true},
+			},
+		}
+
+		assertPlan(t, internal.PlanApply(manifest, current, false), []string{})
+		assertPlan(t, internal.PlanApply(manifest, current, true), []string{
+			"- publication room/alice/data (p3)",
+			"- member room/bob (m2)",
+		})
+	})
+	t.Run("pruneでも名前の無いメンバーは削除しない", func(t *testing.T) {
+		current := &skyway.Channel{
+			Id:       "c1",
+			Name:     "room",
+			Metadata: "meta",
+			Members:  []skyway.Member{{Id: "m1", Name: "alice"}, {Id: "m2"}},
+			Publications: []skyway.Publication{
+				{Id: "p1", PublisherId: "m1", ContentType: "video", IsEnabled: true},
+				{Id: "p2", PublisherId: "m1", ContentType: "audio", IsEnabled: false},
+			},
+		}
+
+		assertPlan(t, internal.PlanApply(manifest, current, true), []string{})
+	})
+	t.Run("typeとsubtypeが異なるメンバーは置き換える", func(t *testing.T) {
+		manifest := internal.ChannelManifest{
+			Name:    "room",
+			Members: []internal.MemberManifest{{Name: "bot", Type: "bot", Subtype: "sfu", Publications: []internal.PublicationManifest{{ContentType: "video"}}}},
+		}
+		current := &skyway.Channel{
+			Id:           "c1",
+			Name:         "room",
+			Members:      []skyway.Member{{Id: "m1", Name: "bot", Type: "person", Subtype: "person"}},
+			Publications: []skyway.Publication{{Id: "p1", PublisherId: "m1", ContentType: "video", IsEnabled: true}},
+		}
+
+		assertPlan(t, internal.PlanApply(manifest, current, false), []string{
+			"- member room/bot (replace to change type)",
+			"+ member room/bot",
+			"+ publication room/bot/video",
+		})
+	})
+	t.Run("TTLが異なるメンバーはTTLを更新し、TTLを無くす場合は置き換える", func(t *testing.T) {
+		expiry := time.Now().Add(time.Hour).Unix()
+		current := &skyway.Channel{
+			Id:      "c1",
+			Name:    "room",
+			Members: []skyway.Member{{Id: "m1", Name: "alice"}, {Id: "m2", Name: "bob", TtlSec: &expiry}, {Id: "m3", Name: "carol", TtlSec: &expiry}},
+		}
+		manifest := internal.ChannelManifest{
+			Name: "room",
+			Members: []internal.MemberManifest{
+				{Name: "alice", Ttl: 3600},
+				{Name: "bob", Ttl: 3600},
+				{Name: "carol"},
+			},
+		}
+
+		assertPlan(t, internal.PlanApply(manifest, current, false), []string{
+			"~ member room/alice (ttl)",
+			"- member room/carol (replace to change ttl)",
+			"+ member room/carol",
+		})
+	})
+	t.Run("同じ名前のメンバーは最初のメンバーに適用し、pruneの場合は残りを削除する", func(t *testing.T) {
+		current := &skyway.Channel{
+			Id:      "c1",
+			Name:    "room",
+			Members: []skyway.Member{{Id: "m1", Name: "alice"}, {Id: "m2", Name: "alice", Metadata: "meta"}},
+		}
+		manifest := internal.ChannelManifest{
+			Name:    "room",
+			Members: []internal.MemberManifest{{Name: "alice", Metadata: "meta"}},
+		}
+
+		actions := internal.PlanApply(manifest, current, false)
+		assertPlan(t, actions, []string{"~ member room/alice (metadata)"})
+		if actions[0].Id != "m1" {
+			t.Errorf("id: %s", actions[0].Id)
+		}
+
+		// the action updates the member shown in the plan
+		var memberId string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request struct {
+				Id     interface{} `json:"id"`
+				Params struct {
+					MemberId string `json:"memberId"`
+				} `json:"params"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			memberId = request.Params.MemberId
+			json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.Id, "result": map[string]interface{}{}})
+		}))
+		defer server.Close()
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"))
+		if err := actions[0].Run(context.Background(), client); err != nil {
+			t.Fatal(err)
+		}
+		if memberId != "m1" {
+			t.Errorf("memberId: %s", memberId)
+		}
+
+		assertPlan(t, internal.PlanApply(manifest, current, true), []string{
+			"~ member room/alice (metadata)",
+			"- member room/alice (m2, duplicate of m1)",
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+// RegistryEntry is a channel which the CLI has created, found or watched.
+type RegistryEntry struct {
+	AppId string `json:"appId"`
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	// Source is the command which recorded the channel first, e.g. "create".
+	Source      string     `json:"source"`
+	FirstSeenAt time.Time  `json:"firstSeenAt"`
+	LastSeenAt  time.Time  `json:"lastSeenAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+}
+
+// Registry is the local record of channels, because the Channel API has no list operation.
+type Registry struct {
+	Channels []RegistryEntry `json:"channels"`
+}
+
+// RegistryPath returns the path of the registry file under the XDG data directory.
+func RegistryPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "skyway-cli", "channels.json"), nil
+}
+
+// LoadRegistry reads the registry file. A missing file is treated as an empty registry.
+func LoadRegistry(path string) (Registry, error) {
+	var registry Registry
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry, nil
+	}
+	if err != nil {
+		return registry, err
+	}
+
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return registry, fmt.Errorf("invalid registry. file: %s err: %v", path, err)
+	}
+	return registry, nil
+}
+
+// Save writes the registry file atomically.
+func (r *Registry) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".channels-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+const (
+	// registryLockTimeout is how long to wait for another process to release the registry.
+	registryLockTimeout = 5 * time.Second
+	// registryLockStale is how old a lock file must be to be regarded as left by a crashed process.
+	registryLockStale = 30 * time.Second
+)
+
+// lockRegistry creates the lock file next to the registry file, waiting while another process holds it.
+// It returns a function which releases the lock.
+func lockRegistry(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(registryLockTimeout)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > registryLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the lock of the registry. remove %s if no other process is running", lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// UpdateRegistry loads the registry file, applies update and saves it.
+// The file is locked while updating, so that concurrent runs of the CLI do not lose each other's entries.
+// The registry is not saved when update returns an error. It returns the updated registry.
+func UpdateRegistry(path string, update func(registry *Registry) error) (Registry, error) {
+	unlock, err := lockRegistry(path)
+	if err != nil {
+		return Registry{}, err
+	}
+	defer unlock()
+
+	registry, err := LoadRegistry(path)
+	if err != nil {
+		return registry, err
+	}
+	if err := update(&registry); err != nil {
+		return registry, err
+	}
+	return registry, registry.Save(path)
+}
+
+func (r *Registry) find(appId string, id string) *RegistryEntry {
+	for i := range r.Channels {
+		if r.Channels[i].AppId == appId && r.Channels[i].Id == id {
+			return &r.Channels[i]
+		}
+	}
+	return nil
+}
+
+// Record adds the channel or updates its name and last seen time.
+func (r *Registry) Record(appId string, channel skyway.Channel, source string, now time.Time) {
+	if entry := r.find(appId, channel.Id); entry != nil {
+		if channel.Name != "" {
+			entry.Name = channel.Name
+		}
+		entry.LastSeenAt = now
+		entry.DeletedAt = nil
+		return
+	}
+
+	r.Channels = append(r.Channels, RegistryEntry{
+		AppId:       appId,
+		Id:          channel.Id,
+		Name:        channel.Name,
+		Source:      source,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	})
+}
+
+// MarkDeleted marks the channel as deleted. Unknown channels are ignored.
+func (r *Registry) MarkDeleted(appId string, id string, now time.Time) {
+	if entry := r.find(appId, id); entry != nil && entry.DeletedAt == nil {
+		entry.DeletedAt = &now
+	}
+}
@@ -0,0 +1,97 @@
+package internal_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/internal"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+func TestRegistry(t *testing.T) {
+	firstSeenAt := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	lastSeenAt := firstSeenAt.Add(time.Hour)
+
+	t.Run("記録したチャンネルの名前と最終確認時刻を更新する", func(t *testing.T) {
+		var registry internal.Registry
+		registry.Record("app", skyway.Channel{Id: "c1", Name: "room"}, "create", firstSeenAt)
+		registry.Record("app", skyway.Channel{Id: "c1"}, "find", lastSeenAt)
+		registry.Record("other", skyway.Channel{Id: "c1", Name: "room"}, "find", lastSeenAt)
+
+		if len(registry.Channels) != 2 {
+			t.Fatalf("channels: %+v", registry.Channels)
+		}
+		entry := registry.Channels[0]
+		if entry.Name != "room" || entry.Source != "create" || !entry.FirstSeenAt.Equal(firstSeenAt) || !entry.LastSeenAt.Equal(lastSeenAt) {
+			t.Errorf("entry: %+v", entry)
+		}
+	})
+
+	t.Run("削除済みにしたチャンネルは再び記録すると削除済みでなくなる", func(t *testing.T) {
+		var registry internal.Registry
+		registry.Record("app", skyway.Channel{Id: "c1", Name: "room"}, "create", firstSeenAt)
+		registry.MarkDeleted("app", "c1", lastSeenAt)
+		registry.MarkDeleted("app", "unknown", lastSeenAt)
+
+		if len(registry.Channels) != 1 || registry.Channels[0].DeletedAt == nil || !registry.Channels[0].DeletedAt.Equal(lastSeenAt) {
+			t.Fatalf("channels: %+v", registry.Channels)
+		}
+
+		registry.Record("app", skyway.Channel{Id: "c1"}, "find", lastSeenAt)
+		if registry.Channels[0].DeletedAt != nil {
+			t.Errorf("entry: %+v", registry.Channels[0])
+		}
+	})
+
+	t.Run("壊れたファイルはエラーにして上書きしない", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+		if err := os.WriteFile(path, []byte("{broken"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := internal.LoadRegistry(path); err == nil {
+			t.Error("err is nil")
+		}
+		_, err := internal.UpdateRegistry(path, func(registry *internal.Registry) error {
+			registry.Record("app", skyway.Channel{Id: "c1"}, "create", firstSeenAt)
+			return nil
+		})
+		if err == nil {
+			t.Error("err is nil")
+		}
+		if data, _ := os.ReadFile(path); string(data) != "{broken" {
+			t.Errorf("file is overwritten: %s", data)
+		}
+	})
+
+	t.Run("同時に更新しても記録が失われない", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "channels.json")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := internal.UpdateRegistry(path, func(registry *internal.Registry) error {
+					registry.Record("app", skyway.Channel{Id: string(rune('a' + i))}, "create", firstSeenAt)
+					return nil
+				})
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		registry, err := internal.LoadRegistry(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(registry.Channels) != 20 {
+			t.Errorf("channels: %d", len(registry.Channels))
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package skyway
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The types in this file model the resources returned by the Channel API.
+// Fields which are not modeled are kept in Unknown and written back when marshaled,
+// so that the output of the CLI never loses data returned by the API.
+
+type Member struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Subtype  string `json:"subtype"`
+	Metadata string `json:"metadata"`
+	// Version is incremented when the member is updated.
+	Version int `json:"version"`
+	// TtlSec is the unix time in seconds when the member expires. It is nil when the member does not expire.
+	TtlSec  *int64                     `json:"ttlSec"`
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+type Codec struct {
+	MimeType   string                     `json:"mimeType"`
+	Parameters map[string]interface{}     `json:"parameters"`
+	Unknown    map[string]json.RawMessage `json:"-"`
+}
+
+type Encoding struct {
+	Id                    string                     `json:"id"`
+	MaxBitrate            *int                       `json:"maxBitrate,omitempty"`
+	ScaleResolutionDownBy *float64                   `json:"scaleResolutionDownBy,omitempty"`
+	MaxFramerate          *float64                   `json:"maxFramerate,omitempty"`
+	Unknown               map[string]json.RawMessage `json:"-"`
+}
+
+type Publication struct {
+	Id                string `json:"id"`
+	PublisherId       string `json:"publisherId"`
+	ContentType       string `json:"contentType"`
+	IsEnabled         bool   `json:"isEnabled"`
+	OriginId          string `json:"originId"`
+	OriginPublisherId string `json:"originPublisherId"`
+	Metadata          string `json:"metadata"`
+	// Type is p2p or sfu.
+	Type              string                     `json:"type"`
+	CodecCapabilities []Codec                    `json:"codecCapabilities"`
+	Encodings         []Encoding                 `json:"encodings"`
+	Unknown           map[string]json.RawMessage `json:"-"`
+}
+
+type Subscription struct {
+	Id            string                     `json:"id"`
+	PublicationId string                     `json:"publicationId"`
+	SubscriberId  string                     `json:"subscriberId"`
+	PublisherId   string                     `json:"publisherId"`
+	ContentType   string                     `json:"contentType"`
+	Unknown       map[string]json.RawMessage `json:"-"`
+}
+
+type Channel struct {
+	Id            string                     `json:"id"`
+	Name          string                     `json:"name"`
+	Metadata      string                     `json:"metadata"`
+	Version       int                        `json:"version"`
+	Members       []Member                   `json:"members"`
+	Publications  []Publication              `json:"publications"`
+	Subscriptions []Subscription             `json:"subscriptions"`
+	Unknown       map[string]json.RawMessage `json:"-"`
+}
+
+// The aliases below have the same fields without the JSON methods, to avoid infinite recursion.
+type memberFields Member
+type codecFields Codec
+type encodingFields Encoding
+type publicationFields Publication
+type subscriptionFields Subscription
+type channelFields Channel
+
+func (m *Member) UnmarshalJSON(data []byte) error {
+	return unmarshalWithUnknown(data, (*memberFields)(m), &m.Unknown)
+}
+
+func (m Member) MarshalJSON() ([]byte, error) {
+	return marshalWithUnknown(memberFields(m), m.Unknown)
+}
+
+func (c *Codec) UnmarshalJSON(data []byte) error {
+	return unmarshalWithUnknown(data, (*codecFields)(c), &c.Unknown)
+}
+
+func (c Codec) MarshalJSON() ([]byte, error) {
+	return marshalWithUnknown(codecFields(c), c.Unknown)
+}
+
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	return unmarshalWithUnknown(data, (*encodingFields)(e), &e.Unknown)
+}
+
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	return marshalWithUnknown(encodingFields(e), e.Unknown)
+}
+
+func (p *Publication) UnmarshalJSON(data []byte) error {
+	return unmarshalWithUnknown(data, (*publicationFields)(p), &p.Unknown)
+}
+
+func (p Publication) MarshalJSON() ([]byte, error) {
+	return marshalWithUnknown(publicationFields(p), p.Unknown)
+}
+
+func (s *Subscription) UnmarshalJSON(data []byte) error {
+	return unmarshalWithUnknown(data, (*subscriptionFields)(s), &s.Unknown)
+}
+
+func (s Subscription) MarshalJSON() ([]byte, error) {
+	return marshalWithUnknown(subscriptionFields(s), s.Unknown)
+}
+
+func (c *Channel) UnmarshalJSON(data []byte) error {
+	return unmarshalWithUnknown(data, (*channelFields)(c), &c.Unknown)
+}
+
+func (c Channel) MarshalJSON() ([]byte, error) {
+	return marshalWithUnknown(channelFields(c), c.Unknown)
+}
+
+var knownFieldsCache sync.Map
+
+// knownFields returns the JSON field names of the struct type t.
+func knownFields(t reflect.Type) map[string]bool {
+	if fields, ok := knownFieldsCache.Load(t); ok {
+		return fields.(map[string]bool)
+	}
+
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		fields[name] = true
+	}
+
+	knownFieldsCache.Store(t, fields)
+	return fields
+}
+
+func unmarshalWithUnknown(data []byte, fields interface{}, unknown *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, fields); err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	known := knownFields(reflect.TypeOf(fields).Elem())
+	*unknown = nil
+	for key, value := range all {
+		// encoding/json matches field names case-insensitively
+		if known[key] || knownFold(known, key) {
+			continue
+		}
+		if *unknown == nil {
+			*unknown = map[string]json.RawMessage{}
+		}
+		(*unknown)[key] = value
+	}
+	return nil
+}
+
+func knownFold(known map[string]bool, key string) bool {
+	for name := range known {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func marshalWithUnknown(fields interface{}, unknown map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(fields)
+	if err != nil || len(unknown) == 0 {
+		return data, err
+	}
+
+	known := knownFields(reflect.TypeOf(fields))
+	var keys []string
+	for key := range unknown {
+		if !known[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	// append the unknown fields after the known ones, keeping the order of the known fields
+	var buffer bytes.Buffer
+	buffer.Write(data[:len(data)-1])
+	for i, key := range keys {
+		if i > 0 || len(data) > 2 {
+			buffer.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(unknown[key])
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
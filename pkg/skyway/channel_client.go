@@ -0,0 +1,322 @@
+package skyway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+type FindChannelParams struct {
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type FindChannelResult struct {
+	Channel Channel `json:"channel"`
+}
+
+type CreateChannelParams struct {
+	Name     string `json:"name,omitempty"`
+	Metadata string `json:"metadata,omitempty"`
+}
+
+type CreateChannelResult struct {
+	Channel Channel `json:"channel"`
+}
+
+type FindOrCreateChannelParams struct {
+	Name     string `json:"name,omitempty"`
+	Metadata string `json:"metadata,omitempty"`
+}
+
+type FindOrCreateChannelResult struct {
+	Channel Channel `json:"channel"`
+}
+
+type DeleteChannelParams struct {
+	Id string `json:"id"`
+}
+
+type UpdateChannelMetadataParams struct {
+	Id       string `json:"id"`
+	Metadata string `json:"metadata"`
+}
+
+type AddMemberParams struct {
+	ChannelId string `json:"channelId"`
+	Name      string `json:"name,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Subtype   string `json:"subtype,omitempty"`
+	Metadata  string `json:"metadata,omitempty"`
+	// TtlSec is the unix time in seconds when the member expires.
+	TtlSec int64 `json:"ttlSec,omitempty"`
+}
+
+type AddMemberResult struct {
+	MemberId string `json:"memberId"`
+}
+
+type LeaveChannelParams struct {
+	ChannelId string `json:"channelId"`
+	Id        string `json:"id"`
+}
+
+type UpdateMemberTtlParams struct {
+	ChannelId string `json:"channelId"`
+	MemberId  string `json:"memberId"`
+	// TtlSec is the unix time in seconds when the member expires.
+	TtlSec int64 `json:"ttlSec"`
+}
+
+type UpdateMemberMetadataParams struct {
+	ChannelId string `json:"channelId"`
+	MemberId  string `json:"memberId"`
+	Metadata  string `json:"metadata"`
+}
+
+type PublishStreamParams struct {
+	ChannelId   string `json:"channelId"`
+	PublisherId string `json:"publisherId"`
+	ContentType string `json:"contentType"`
+	Metadata    string `json:"metadata,omitempty"`
+	// Origin is the id of the publication which this publication forwards.
+	Origin    string `json:"origin,omitempty"`
+	IsEnabled *bool  `json:"isEnabled,omitempty"`
+}
+
+type PublishStreamResult struct {
+	Id string `json:"id"`
+}
+
+type PublicationParams struct {
+	ChannelId     string `json:"channelId"`
+	PublicationId string `json:"publicationId"`
+}
+
+type UpdatePublicationMetadataParams struct {
+	ChannelId     string `json:"channelId"`
+	PublicationId string `json:"publicationId"`
+	Metadata      string `json:"metadata"`
+}
+
+type SubscribeStreamParams struct {
+	ChannelId     string `json:"channelId"`
+	SubscriberId  string `json:"subscriberId"`
+	PublicationId string `json:"publicationId"`
+}
+
+type SubscribeStreamResult struct {
+	Id string `json:"id"`
+}
+
+type UnsubscribeStreamParams struct {
+	ChannelId      string `json:"channelId"`
+	SubscriptionId string `json:"subscriptionId"`
+}
+
+// ChannelClient is a client of the SkyWay Channel API.
+// The token source should provide a SkyWay Admin Auth Token.
+type ChannelClient struct {
+	url        string
+	tokens     TokenSource
+	httpClient *http.Client
+	userAgent  string
+}
+
+func NewChannelClient(url string, tokens TokenSource, opts ...Option) *ChannelClient {
+	options := newClientOptions(opts)
+	return &ChannelClient{
+		url:        url,
+		tokens:     tokens,
+		httpClient: retryingHTTPClient(options.httpClient, options.retryPolicy),
+		userAgent:  options.userAgent,
+	}
+}
+
+// nonIdempotentMethods create a new resource on every call,
+// so they are not retried when the server may have processed the request.
+var nonIdempotentMethods = map[string]bool{
+	"createChannel":   true,
+	"addMember":       true,
+	"publishStream":   true,
+	"subscribeStream": true,
+}
+
+// call calls the JSON-RPC method and stores its result in result.
+// JSON-RPC error objects and HTTP error responses are returned as *RPCError.
+func (c *ChannelClient) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	if nonIdempotentMethods[method] {
+		ctx = withNonIdempotent(ctx)
+	}
+
+	rpcClient := jsonrpc.NewClientWithOpts(c.url, &jsonrpc.RPCClientOpts{
+		HTTPClient: c.httpClient,
+		CustomHeaders: map[string]string{
+			"Authorization": "Bearer " + token,
+			"User-Agent":    c.userAgent,
+		},
+	})
+	response, err := rpcClient.Call(ctx, method, params)
+
+	var httpError *jsonrpc.HTTPError
+	if errors.As(err, &httpError) {
+		if response != nil && response.Error != nil {
+			return &RPCError{Method: method, StatusCode: httpError.Code, Code: response.Error.Code, Message: response.Error.Message, Data: response.Error.Data}
+		}
+		return &RPCError{Method: method, StatusCode: httpError.Code, Message: http.StatusText(httpError.Code)}
+	}
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return &RPCError{Method: method, Code: response.Error.Code, Message: response.Error.Message, Data: response.Error.Data}
+	}
+	return response.GetObject(result)
+}
+
+// FindChannel finds a channel by id or name.
+// *NotFoundError, which matches ErrNotFound, is returned when the channel does not exist.
+func (c *ChannelClient) FindChannel(ctx context.Context, id string, name string) (Channel, error) {
+	var channel *FindChannelResult
+	err := c.call(ctx, "findChannel", &FindChannelParams{Id: id, Name: name}, &channel)
+
+	if err != nil {
+		return Channel{}, err
+	}
+	if channel == nil {
+		return Channel{}, &NotFoundError{Resource: "channel", Id: id, Name: name}
+	}
+
+	return channel.Channel, nil
+}
+
+func (c *ChannelClient) CreateChannel(ctx context.Context, name string, metadata string) (Channel, error) {
+	var result *CreateChannelResult
+	err := c.call(ctx, "createChannel", &CreateChannelParams{Name: name, Metadata: metadata}, &result)
+
+	if err != nil || result == nil {
+		return Channel{}, err
+	}
+
+	return result.Channel, nil
+}
+
+// FindOrCreateChannel returns the channel with the given name, creating it when it does not exist.
+//
+// The returned bool reports whether the channel was created by this call, on a best-effort basis.
+// findOrCreateChannel does not tell whether it created the channel, so the channel is looked up first.
+// A channel which already existed is never reported as created, but a channel which another client created
+// between the two calls is reported as created unless its metadata differs from metadata.
+func (c *ChannelClient) FindOrCreateChannel(ctx context.Context, name string, metadata string) (Channel, bool, error) {
+	channel, err := c.FindChannel(ctx, "", name)
+	if err == nil {
+		return channel, false, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return Channel{}, false, err
+	}
+
+	var result *FindOrCreateChannelResult
+	err = c.call(ctx, "findOrCreateChannel", &FindOrCreateChannelParams{Name: name, Metadata: metadata}, &result)
+
+	if err != nil || result == nil {
+		return Channel{}, false, err
+	}
+
+	// the metadata is only set when the channel is created by this call
+	return result.Channel, result.Channel.Metadata == metadata, nil
+}
+
+func (c *ChannelClient) DeleteChannel(ctx context.Context, id string) error {
+	var result interface{}
+	return c.call(ctx, "deleteChannel", &DeleteChannelParams{Id: id}, &result)
+}
+
+func (c *ChannelClient) UpdateChannelMetadata(ctx context.Context, id string, metadata string) error {
+	var result interface{}
+	return c.call(ctx, "updateChannelMetadata", &UpdateChannelMetadataParams{Id: id, Metadata: metadata}, &result)
+}
+
+// AddMember adds a member to a channel and returns its id.
+func (c *ChannelClient) AddMember(ctx context.Context, params AddMemberParams) (string, error) {
+	var result *AddMemberResult
+	err := c.call(ctx, "addMember", &params, &result)
+
+	if err != nil || result == nil {
+		return "", err
+	}
+
+	return result.MemberId, nil
+}
+
+func (c *ChannelClient) LeaveChannel(ctx context.Context, channelId string, memberId string) error {
+	var result interface{}
+	return c.call(ctx, "leaveChannel", &LeaveChannelParams{ChannelId: channelId, Id: memberId}, &result)
+}
+
+// UpdateMemberTtl updates the time when the member expires. ttlSec is a unix time in seconds.
+func (c *ChannelClient) UpdateMemberTtl(ctx context.Context, channelId string, memberId string, ttlSec int64) error {
+	var result interface{}
+	return c.call(ctx, "updateMemberTtl", &UpdateMemberTtlParams{ChannelId: channelId, MemberId: memberId, TtlSec: ttlSec}, &result)
+}
+
+func (c *ChannelClient) UpdateMemberMetadata(ctx context.Context, channelId string, memberId string, metadata string) error {
+	var result interface{}
+	return c.call(ctx, "updateMemberMetadata", &UpdateMemberMetadataParams{ChannelId: channelId, MemberId: memberId, Metadata: metadata}, &result)
+}
+
+// PublishStream publishes a stream on behalf of a member and returns the publication id.
+func (c *ChannelClient) PublishStream(ctx context.Context, params PublishStreamParams) (string, error) {
+	var result *PublishStreamResult
+	err := c.call(ctx, "publishStream", &params, &result)
+
+	if err != nil || result == nil {
+		return "", err
+	}
+
+	return result.Id, nil
+}
+
+func (c *ChannelClient) UnpublishStream(ctx context.Context, channelId string, publicationId string) error {
+	var result interface{}
+	return c.call(ctx, "unpublishStream", &PublicationParams{ChannelId: channelId, PublicationId: publicationId}, &result)
+}
+
+func (c *ChannelClient) EnablePublication(ctx context.Context, channelId string, publicationId string) error {
+	var result interface{}
+	return c.call(ctx, "enablePublication", &PublicationParams{ChannelId: channelId, PublicationId: publicationId}, &result)
+}
+
+func (c *ChannelClient) DisablePublication(ctx context.Context, channelId string, publicationId string) error {
+	var result interface{}
+	return c.call(ctx, "disablePublication", &PublicationParams{ChannelId: channelId, PublicationId: publicationId}, &result)
+}
+
+func (c *ChannelClient) UpdatePublicationMetadata(ctx context.Context, channelId string, publicationId string, metadata string) error {
+	var result interface{}
+	return c.call(ctx, "updatePublicationMetadata", &UpdatePublicationMetadataParams{ChannelId: channelId, PublicationId: publicationId, Metadata: metadata}, &result)
+}
+
+// SubscribeStream subscribes to a publication on behalf of a member and returns the subscription id.
+func (c *ChannelClient) SubscribeStream(ctx context.Context, channelId string, subscriberId string, publicationId string) (string, error) {
+	var result *SubscribeStreamResult
+	err := c.call(ctx, "subscribeStream", &SubscribeStreamParams{ChannelId: channelId, SubscriberId: subscriberId, PublicationId: publicationId}, &result)
+
+	if err != nil || result == nil {
+		return "", err
+	}
+
+	return result.Id, nil
+}
+
+func (c *ChannelClient) UnsubscribeStream(ctx context.Context, channelId string, subscriptionId string) error {
+	var result interface{}
+	return c.call(ctx, "unsubscribeStream", &UnsubscribeStreamParams{ChannelId: channelId, SubscriptionId: subscriptionId}, &result)
+}
@@ -0,0 +1,149 @@
+package skyway
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventType is the type of a channel event notified by the RTC API.
+type EventType string
+
+const (
+	EventChannelClosed              EventType = "ChannelClosed"
+	EventChannelMetadataUpdated     EventType = "ChannelMetadataUpdated"
+	EventMemberAdded                EventType = "MemberAdded"
+	EventMemberRemoved              EventType = "MemberRemoved"
+	EventMemberMetadataUpdated      EventType = "MemberMetadataUpdated"
+	EventStreamPublished            EventType = "StreamPublished"
+	EventStreamUnpublished          EventType = "StreamUnpublished"
+	EventPublicationMetadataUpdated EventType = "PublicationMetadataUpdated"
+	EventPublicationEnabled         EventType = "PublicationEnabled"
+	EventPublicationDisabled        EventType = "PublicationDisabled"
+	EventStreamSubscribed           EventType = "StreamSubscribed"
+	EventStreamUnsubscribed         EventType = "StreamUnsubscribed"
+)
+
+// EventTypes are the known event types. Events of other types are still delivered with their type as notified.
+var EventTypes = []EventType{
+	EventChannelClosed,
+	EventChannelMetadataUpdated,
+	EventMemberAdded,
+	EventMemberRemoved,
+	EventMemberMetadataUpdated,
+	EventStreamPublished,
+	EventStreamUnpublished,
+	EventPublicationMetadataUpdated,
+	EventPublicationEnabled,
+	EventPublicationDisabled,
+	EventStreamSubscribed,
+	EventStreamUnsubscribed,
+}
+
+// ParseEventType returns the known event type which matches name case-insensitively.
+func ParseEventType(name string) (EventType, error) {
+	for _, eventType := range EventTypes {
+		if strings.EqualFold(string(eventType), name) {
+			return eventType, nil
+		}
+	}
+
+	names := make([]string, len(EventTypes))
+	for i, eventType := range EventTypes {
+		names[i] = string(eventType)
+	}
+	return "", fmt.Errorf("unknown event type: %s. valid types: %s", name, strings.Join(names, ", "))
+}
+
+// Event is a channel event notified by subscribeChannelEvents.
+// Only the resources which the event concerns are set. For example, MemberAdded has Member and Channel.
+type Event struct {
+	Type         EventType     `json:"type"`
+	AppId        string        `json:"appId,omitempty"`
+	ChannelId    string        `json:"channelId"`
+	Version      int           `json:"version,omitempty"`
+	Channel      *Channel      `json:"channel,omitempty"`
+	Member       *Member       `json:"member,omitempty"`
+	Publication  *Publication  `json:"publication,omitempty"`
+	Subscription *Subscription `json:"subscription,omitempty"`
+	// ReceivedAt is when the event was received. It is zero for events which were not received by EventStream.
+	ReceivedAt time.Time `json:"receivedAt"`
+
+	// Raw is the notification as received, which has the fields not modeled by Event.
+	Raw json.RawMessage `json:"-"`
+}
+
+// MemberIds returns the ids of the members which the event concerns,
+// such as the publisher of a publication and the subscriber of a subscription.
+func (e *Event) MemberIds() []string {
+	var ids []string
+	add := func(id string) {
+		if id == "" {
+			return
+		}
+		for _, added := range ids {
+			if added == id {
+				return
+			}
+		}
+		ids = append(ids, id)
+	}
+
+	if e.Member != nil {
+		add(e.Member.Id)
+	}
+	if e.Publication != nil {
+		add(e.Publication.PublisherId)
+	}
+	if e.Subscription != nil {
+		add(e.Subscription.SubscriberId)
+		add(e.Subscription.PublisherId)
+	}
+	return ids
+}
+
+// ParseEvent parses a message received from the RTC API.
+// It returns nil without an error when the message is not a channel event, such as the response to subscribeChannelEvents.
+func ParseEvent(message []byte) (*Event, error) {
+	var notification struct {
+		Method string `json:"method"`
+		Params struct {
+			Event *struct {
+				Type      EventType `json:"type"`
+				AppId     string    `json:"appId"`
+				ChannelId string    `json:"channelId"`
+				Version   int       `json:"version"`
+				Data      struct {
+					Channel      *Channel      `json:"channel"`
+					Member       *Member       `json:"member"`
+					Publication  *Publication  `json:"publication"`
+					Subscription *Subscription `json:"subscription"`
+				} `json:"data"`
+			} `json:"event"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &notification); err != nil {
+		return nil, fmt.Errorf("failed to parse channel event: %w", err)
+	}
+	if notification.Method != "channelEventNotification" || notification.Params.Event == nil {
+		return nil, nil
+	}
+
+	event := notification.Params.Event
+	channelId := event.ChannelId
+	if channelId == "" && event.Data.Channel != nil {
+		channelId = event.Data.Channel.Id
+	}
+	return &Event{
+		Type:         event.Type,
+		AppId:        event.AppId,
+		ChannelId:    channelId,
+		Version:      event.Version,
+		Channel:      event.Data.Channel,
+		Member:       event.Data.Member,
+		Publication:  event.Data.Publication,
+		Subscription: event.Data.Subscription,
+		Raw:          append(json.RawMessage(nil), message...),
+	}, nil
+}
@@ -0,0 +1,63 @@
+package skyway_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+func TestParseEvent(t *testing.T) {
+	t.Run("チャンネルイベントの通知を型付きのイベントにする", func(t *testing.T) {
+		message, err := os.ReadFile(filepath.Join("testdata", "rtc_api", "StreamSubscribed.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		event, err := skyway.ParseEvent(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Type != skyway.EventStreamSubscribed || event.ChannelId != "5f4c7c3e-6a8e-4b4f-9a5e-2a1f0f3b7c11" || event.Version != 7 {
+			t.Errorf("event: %+v", event)
+		}
+		if event.Subscription == nil || event.Subscription.PublicationId != "p1" || event.Member != nil {
+			t.Errorf("subscription: %+v member: %+v", event.Subscription, event.Member)
+		}
+		if ids := event.MemberIds(); len(ids) != 2 || ids[0] != "m2" || ids[1] != "m1" {
+			t.Errorf("member ids: %v", ids)
+		}
+		if string(event.Raw) != string(message) {
+			t.Errorf("raw: %s", event.Raw)
+		}
+	})
+
+	t.Run("チャンネルイベントでないメッセージはnilを返す", func(t *testing.T) {
+		event, err := skyway.ParseEvent([]byte(`{"jsonrpc":"2.0","id":"1","result":{}}`))
+		if event != nil || err != nil {
+			t.Errorf("event: %+v err: %v", event, err)
+		}
+	})
+
+	t.Run("JSONでないメッセージはエラーを返す", func(t *testing.T) {
+		if _, err := skyway.ParseEvent([]byte(`not json`)); err == nil {
+			t.Error("err is nil")
+		}
+	})
+}
+
+func TestParseEventType(t *testing.T) {
+	t.Run("大文字小文字を区別せずに解釈する", func(t *testing.T) {
+		eventType, err := skyway.ParseEventType("memberadded")
+		if err != nil || eventType != skyway.EventMemberAdded {
+			t.Errorf("type: %s err: %v", eventType, err)
+		}
+	})
+
+	t.Run("不明な種類はエラーを返す", func(t *testing.T) {
+		if _, err := skyway.ParseEventType("MemberJoined"); err == nil {
+			t.Error("err is nil")
+		}
+	})
+}
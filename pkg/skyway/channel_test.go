@@ -0,0 +1,182 @@
+package skyway_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+// serveFixture returns a Channel API server which responds with the recorded response of the method.
+func serveFixture(t *testing.T, method string) *httptest.Server {
+	t.Helper()
+
+	fixture, err := os.ReadFile(filepath.Join("testdata", "channel_api", method+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Method != method {
+			t.Errorf("unexpected request. method: %s err: %v", request.Method, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// fixtureChannel returns result.channel of the recorded response as a generic JSON value.
+func fixtureChannel(t *testing.T, method string) interface{} {
+	t.Helper()
+
+	fixture, err := os.ReadFile(filepath.Join("testdata", "channel_api", method+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response struct {
+		Result struct {
+			Channel interface{} `json:"channel"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(fixture, &response); err != nil {
+		t.Fatal(err)
+	}
+	return response.Result.Channel
+}
+
+func assertRoundTrip(t *testing.T, channel skyway.Channel, expected interface{}) {
+	t.Helper()
+
+	data, err := json.Marshal(channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual interface{}
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("round trip changed data.\nexpected: %v\nactual:   %v", expected, actual)
+	}
+}
+
+func TestChannelModel(t *testing.T) {
+	t.Run("findChannel", func(t *testing.T) {
+		server := serveFixture(t, "findChannel")
+
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"))
+		channel, err := client.FindChannel(context.Background(), "5f4c7c3e-6a8e-4b4f-9a5e-2a1f0f3b7c11", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("全てのフィールドを読み込む", func(t *testing.T) {
+			if channel.Version != 7 {
+				t.Errorf("version: %d", channel.Version)
+			}
+			if channel.Members[0].Version != 3 {
+				t.Errorf("member version: %d", channel.Members[0].Version)
+			}
+			if channel.Members[1].TtlSec != nil {
+				t.Errorf("ttlSec of the member without TTL: %v", *channel.Members[1].TtlSec)
+			}
+			if channel.Members[0].TtlSec == nil || *channel.Members[0].TtlSec != 1792209171 {
+				t.Errorf("ttlSec: %v", channel.Members[0].TtlSec)
+			}
+			publication := channel.Publications[0]
+			if publication.Type != "sfu" {
+				t.Errorf("type: %s", publication.Type)
+			}
+			if len(publication.CodecCapabilities) != 2 || publication.CodecCapabilities[1].MimeType != "video/h264" {
+				t.Errorf("codecCapabilities: %v", publication.CodecCapabilities)
+			}
+			if len(publication.Encodings) != 2 || publication.Encodings[1].Id != "high" || *publication.Encodings[1].MaxFramerate != 30 {
+				t.Errorf("encodings: %v", publication.Encodings)
+			}
+			if channel.Publications[1].OriginId != publication.Id || channel.Publications[1].OriginPublisherId != publication.PublisherId {
+				t.Errorf("origin: %v", channel.Publications[1])
+			}
+			if channel.Subscriptions[0].PublisherId != channel.Members[1].Id || channel.Subscriptions[0].ContentType != "video" {
+				t.Errorf("subscription: %v", channel.Subscriptions[0])
+			}
+		})
+		t.Run("未知のフィールドを保持する", func(t *testing.T) {
+			if _, ok := channel.Members[0].Unknown["isWaitingSubscribeChannelEvents"]; !ok {
+				t.Errorf("member unknown fields: %v", channel.Members[0].Unknown)
+			}
+			if _, ok := channel.Publications[0].Unknown["channelId"]; !ok {
+				t.Errorf("publication unknown fields: %v", channel.Publications[0].Unknown)
+			}
+			if _, ok := channel.Publications[0].CodecCapabilities[1].Unknown["rtcpFeedback"]; !ok {
+				t.Errorf("codec unknown fields: %v", channel.Publications[0].CodecCapabilities[1].Unknown)
+			}
+			if _, ok := channel.Subscriptions[0].Unknown["preferredEncodingId"]; !ok {
+				t.Errorf("subscription unknown fields: %v", channel.Subscriptions[0].Unknown)
+			}
+		})
+		t.Run("JSONに戻してもデータが失われない", func(t *testing.T) {
+			assertRoundTrip(t, channel, fixtureChannel(t, "findChannel"))
+		})
+	})
+	t.Run("createChannel", func(t *testing.T) {
+		server := serveFixture(t, "createChannel")
+
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"))
+		channel, err := client.CreateChannel(context.Background(), "room", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("JSONに戻してもデータが失われない", func(t *testing.T) {
+			assertRoundTrip(t, channel, fixtureChannel(t, "createChannel"))
+		})
+	})
+}
+
+func TestFindOrCreateChannel(t *testing.T) {
+	// serveFindOrCreate returns a server which has no channel for findChannel, and returns a channel with metadata for findOrCreateChannel
+	serveFindOrCreate := func(t *testing.T, metadata string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request struct {
+				Id     interface{} `json:"id"`
+				Method string      `json:"method"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			var result interface{}
+			if request.Method == "findOrCreateChannel" {
+				result = map[string]interface{}{"channel": map[string]interface{}{"id": "c1", "name": "room", "metadata": metadata}}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.Id, "result": result})
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("存在しないチャンネルを作成した場合はcreatedを返す", func(t *testing.T) {
+		client := skyway.NewChannelClient(serveFindOrCreate(t, "meta").URL, skyway.StaticTokenSource("token"))
+		channel, created, err := client.FindOrCreateChannel(context.Background(), "room", "meta")
+		if err != nil || channel.Id != "c1" || !created {
+			t.Errorf("channel: %+v created: %v err: %v", channel, created, err)
+		}
+	})
+
+	t.Run("他のクライアントが同時に作成したチャンネルはメタデータが異なればcreatedにしない", func(t *testing.T) {
+		client := skyway.NewChannelClient(serveFindOrCreate(t, "other").URL, skyway.StaticTokenSource("token"))
+		_, created, err := client.FindOrCreateChannel(context.Background(), "room", "meta")
+		if err != nil || created {
+			t.Errorf("created: %v err: %v", created, err)
+		}
+	})
+}
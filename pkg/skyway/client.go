@@ -0,0 +1,139 @@
+// Package skyway provides clients for the SkyWay Channel API, Recording API and RTC API.
+//
+// The clients take a context.Context for every call, a TokenSource to authenticate requests,
+// and options to inject an *http.Client or a WebSocket dialer.
+package skyway
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+)
+
+// TokenSource provides the SkyWay Auth Token or SkyWay Admin Auth Token for each request.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns a TokenSource which always provides the same token.
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// tokenExpiry returns the exp claim of the token, or the zero time when the token has no exp claim.
+// The signature is not verified because the token is only inspected to renew it in time.
+func tokenExpiry(token string) time.Time {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return time.Time{}
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return time.Time{}
+	}
+	return exp.Time
+}
+
+type reuseTokenSource struct {
+	source        TokenSource
+	refreshBefore time.Duration
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// ReuseTokenSource returns a TokenSource which reuses the token of source until refreshBefore its exp claim,
+// and then takes a new token from source. Tokens without an exp claim are reused forever.
+// It is safe for concurrent use, so that long-running commands can share it between clients.
+func ReuseTokenSource(source TokenSource, refreshBefore time.Duration) TokenSource {
+	return &reuseTokenSource{source: source, refreshBefore: refreshBefore}
+}
+
+func (s *reuseTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.expiry.IsZero() || time.Until(s.expiry) > s.refreshBefore) {
+		return s.token, nil
+	}
+
+	token, err := s.source.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expiry = tokenExpiry(token)
+	return token, nil
+}
+
+// DefaultHTTPTimeout is the timeout of the default HTTP client of the clients, which limits a request including its retries,
+// so that a request to a server which does not respond fails even when its context has no deadline.
+const DefaultHTTPTimeout = time.Minute
+
+type clientOptions struct {
+	httpClient      *http.Client
+	dialer          *websocket.Dialer
+	userAgent       string
+	retryPolicy     RetryPolicy
+	reconnectPolicy RetryPolicy
+}
+
+// Option configures a client.
+type Option func(*clientOptions)
+
+// WithHTTPClient sets the HTTP client used by ChannelClient, RecordingClient and Webhook.
+// The default client has DefaultHTTPTimeout as its timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithRetryPolicy sets the retry policy of ChannelClient and RecordingClient.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithDialer sets the WebSocket dialer used by EventStream.
+func WithDialer(dialer *websocket.Dialer) Option {
+	return func(o *clientOptions) {
+		o.dialer = dialer
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests.
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+func newClientOptions(opts []Option) clientOptions {
+	options := clientOptions{
+		httpClient:      &http.Client{Timeout: DefaultHTTPTimeout},
+		dialer:          websocket.DefaultDialer,
+		userAgent:       DefaultUserAgent,
+		retryPolicy:     DefaultRetryPolicy,
+		reconnectPolicy: DefaultReconnectPolicy,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
@@ -0,0 +1,9 @@
+package skyway
+
+import "runtime"
+
+var goVersion = runtime.Version()
+var osName = runtime.GOOS
+
+// DefaultUserAgent is the User-Agent header sent when WithUserAgent is not given.
+var DefaultUserAgent = "skyway-cli/0.0.1 (" + osName + "; " + goVersion + "; Go-http-client/1.1; +https://github.com/kadoshita/skyway-cli)"
@@ -0,0 +1,115 @@
+package skyway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Kinds of API errors. RPCError and APIError match them with errors.Is.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// errorKind returns the kind of an error with the HTTP status code, or nil when it has no kind.
+func errorKind(statusCode int) error {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// NotFoundError is returned when the requested resource does not exist.
+type NotFoundError struct {
+	// Resource is the kind of the resource, such as "channel".
+	Resource string
+	Id       string
+	Name     string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Id != "" {
+		return fmt.Sprintf("%s not found. id: %s", e.Resource, e.Id)
+	}
+	return fmt.Sprintf("%s not found. name: %s", e.Resource, e.Name)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// RPCError is an error object returned by a JSON-RPC API, such as the Channel API.
+type RPCError struct {
+	Method string `json:"method"`
+	// StatusCode is the HTTP status code of the response.
+	// It is 0 when the error object was returned with 200 OK.
+	StatusCode int         `json:"statusCode,omitempty"`
+	Code       int         `json:"code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s failed: %s (code: %d", e.Method, e.Message, e.Code)
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, ", status: %d", e.StatusCode)
+	}
+	b.WriteString(")")
+	if e.Data != nil {
+		if data, err := json.Marshal(e.Data); err == nil {
+			fmt.Fprintf(&b, " data: %s", data)
+		}
+	}
+	return b.String()
+}
+
+// Is reports whether the error is of the kind target, such as ErrNotFound.
+// JSON-RPC error codes which mirror HTTP status codes are treated like the status codes.
+func (e *RPCError) Is(target error) bool {
+	if kind := errorKind(e.StatusCode); kind != nil {
+		return kind == target
+	}
+	kind := errorKind(e.Code)
+	return kind != nil && kind == target
+}
+
+// APIError is an RFC 7807 problem details response of a REST API, such as the Recording API.
+type APIError struct {
+	StatusCode int `json:"statusCode"`
+	CommonErrorResponse
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	title := e.Title
+	if title == "" {
+		title = http.StatusText(e.StatusCode)
+	}
+	fmt.Fprintf(&b, "%d %s", e.StatusCode, title)
+	if e.Detail != "" {
+		fmt.Fprintf(&b, ": %s", e.Detail)
+	}
+	if e.Type != "" && e.Type != "about:blank" {
+		fmt.Fprintf(&b, " (type: %s)", e.Type)
+	}
+	return b.String()
+}
+
+// Is reports whether the error is of the kind target, such as ErrNotFound.
+func (e *APIError) Is(target error) bool {
+	kind := errorKind(e.StatusCode)
+	return kind != nil && kind == target
+}
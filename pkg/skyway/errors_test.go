@@ -0,0 +1,93 @@
+package skyway_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+// serveResponse returns a server which always responds with status and body.
+func serveResponse(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestErrors(t *testing.T) {
+	noRetry := skyway.WithRetryPolicy(skyway.RetryPolicy{MaxAttempts: 1})
+
+	t.Run("JSON-RPCのエラーオブジェクトをRPCErrorとして返す", func(t *testing.T) {
+		server := serveResponse(t, http.StatusUnauthorized, `{"jsonrpc":"2.0","id":0,"error":{"code":-32001,"message":"Invalid token","data":{"reason":"expired"}}}`)
+
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"), noRetry)
+		_, err := client.FindChannel(context.Background(), "", "room")
+
+		var rpcError *skyway.RPCError
+		if !errors.As(err, &rpcError) {
+			t.Fatalf("err: %v", err)
+		}
+		if rpcError.Method != "findChannel" || rpcError.Code != -32001 || rpcError.StatusCode != http.StatusUnauthorized || rpcError.Data == nil {
+			t.Errorf("rpcError: %+v", rpcError)
+		}
+		if !errors.Is(err, skyway.ErrUnauthorized) || errors.Is(err, skyway.ErrNotFound) {
+			t.Errorf("kind of err: %v", err)
+		}
+	})
+
+	t.Run("エラーオブジェクトの無いHTTPエラーもRPCErrorとして返す", func(t *testing.T) {
+		server := serveResponse(t, http.StatusForbidden, ``)
+
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"), noRetry)
+		_, err := client.FindChannel(context.Background(), "", "room")
+
+		if !errors.Is(err, skyway.ErrForbidden) {
+			t.Errorf("err: %v", err)
+		}
+	})
+
+	t.Run("チャンネルが存在しない場合はNotFoundErrorを返す", func(t *testing.T) {
+		server := serveResponse(t, http.StatusOK, `{"jsonrpc":"2.0","id":0,"result":null}`)
+
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"), noRetry)
+		_, err := client.FindChannel(context.Background(), "", "room")
+
+		var notFoundError *skyway.NotFoundError
+		if !errors.As(err, &notFoundError) || notFoundError.Name != "room" {
+			t.Fatalf("err: %v", err)
+		}
+		if !errors.Is(err, skyway.ErrNotFound) || err.Error() != "channel not found. name: room" {
+			t.Errorf("err: %v", err)
+		}
+	})
+
+	t.Run("RFC 7807のエラーレスポンスをAPIErrorとして返す", func(t *testing.T) {
+		server := serveResponse(t, http.StatusNotFound, `{"type":"about:blank","title":"Not Found","status":404,"detail":"session not found","instance":"/channels/c/sessions/s"}`)
+
+		client := skyway.NewRecordingClient(server.URL, skyway.StaticTokenSource("token"), noRetry)
+		_, err := client.GetSession(context.Background(), "c", "s")
+
+		var apiError *skyway.APIError
+		if !errors.As(err, &apiError) {
+			t.Fatalf("err: %v", err)
+		}
+		if apiError.StatusCode != http.StatusNotFound || apiError.Detail != "session not found" || apiError.Instance != "/channels/c/sessions/s" {
+			t.Errorf("apiError: %+v", apiError)
+		}
+		if !errors.Is(err, skyway.ErrNotFound) {
+			t.Errorf("kind of err: %v", err)
+		}
+		if err.Error() != "failed to get recording session: 404 Not Found: session not found" {
+			t.Errorf("message: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,482 @@
+package skyway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const subscribeChannelEventsRequest = `{
+	"id":"%s",
+	"jsonrpc":"2.0",
+	"method":"subscribeChannelEvents",
+	"params":{
+		"authToken":"%s",
+		"appId":"%s",
+		"channelId":"%s"
+	}
+}`
+
+const updateAuthTokenRequest = `{
+	"id":"%s",
+	"jsonrpc":"2.0",
+	"method":"updateAuthToken",
+	"params":{
+		"authToken":"%s",
+		"appId":"%s"
+	}
+}`
+
+const (
+	// tokenRefreshBefore is how long before its expiry the token of the connection is updated.
+	// Tokens shorter than twice of it are updated at half of their remaining lifetime.
+	tokenRefreshBefore = 5 * time.Minute
+	// tokenRetryInterval is how often a new token is requested when the token source returns the current token.
+	tokenRetryInterval = 10 * time.Second
+	// pingInterval is how often a ping is sent to check that the connection is alive.
+	pingInterval = 30 * time.Second
+	// pongWait is how long to wait for any message or pong before the connection is regarded as dead.
+	pongWait = 2 * pingInterval
+	// closeWait is how long to wait for writing the close message on shutdown.
+	closeWait = time.Second
+	// eventQueueSize is how many events SubscribeChannels keeps for a slow handler before dropping the oldest ones.
+	eventQueueSize = 4096
+)
+
+// DefaultReconnectPolicy is used by EventStream when no reconnect policy is given.
+// Its MaxAttempts is 0, so that EventStream reconnects until the context is done.
+var DefaultReconnectPolicy = RetryPolicy{
+	MaxAttempts:    0,
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+	Jitter:         0.2,
+}
+
+// WithReconnectPolicy sets the reconnect policy of EventStream.
+// MaxAttempts is the number of consecutive reconnect attempts, and EventStream reconnects without limit when it is 0.
+func WithReconnectPolicy(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.reconnectPolicy = policy
+	}
+}
+
+// EventStream subscribes to channel events through the SkyWay RTC API.
+// The token source should provide a SkyWay Auth Token which can read the channel.
+//
+// When the connection is lost, EventStream reconnects with backoff and subscribes again.
+// Events which occur while reconnecting are not delivered.
+//
+// The token of the connection is updated with updateAuthToken before it expires, with a new token from the token source.
+// When the update fails, EventStream reconnects with a new token.
+// Use ReuseTokenSource with a source which signs a new token on each call.
+type EventStream struct {
+	url    string
+	appId  string
+	tokens TokenSource
+	dialer *websocket.Dialer
+	policy RetryPolicy
+
+	// OnReconnect is called when the connection is lost, before waiting to reconnect. It may be nil.
+	// attempt is the number of consecutive reconnect attempts, starting from 1.
+	OnReconnect func(err error, attempt int, wait time.Duration)
+}
+
+func NewEventStream(url string, appId string, tokens TokenSource, opts ...Option) *EventStream {
+	options := newClientOptions(opts)
+	return &EventStream{
+		url:    url,
+		appId:  appId,
+		tokens: tokens,
+		dialer: options.dialer,
+		policy: options.reconnectPolicy,
+	}
+}
+
+// isPermanent reports whether reconnecting cannot fix err, such as an invalid token.
+func isPermanent(err error) bool {
+	var rpcError *RPCError
+	if errors.As(err, &rpcError) {
+		return rpcError.Method == "subscribeChannelEvents"
+	}
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode >= 400 && apiError.StatusCode < 500 && apiError.StatusCode != http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Subscribe sends the events of the channel to handler as raw JSON strings until ctx is done.
+//
+// It returns nil when ctx is done.
+// It returns an error when the first connection fails, when the subscription is rejected,
+// or when reconnecting fails more than the MaxAttempts of the reconnect policy.
+func (s *EventStream) Subscribe(ctx context.Context, channelId string, handler chan<- string) error {
+	subscribed := false
+	attempt := 0
+	for {
+		err := s.subscribe(ctx, channelId, handler, func() {
+			subscribed = true
+			attempt = 0
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !subscribed || isPermanent(err) {
+			return err
+		}
+
+		attempt++
+		if s.policy.MaxAttempts > 0 && attempt > s.policy.MaxAttempts {
+			return fmt.Errorf("failed to reconnect after %d attempts: %w", s.policy.MaxAttempts, err)
+		}
+		wait := s.policy.backoff(attempt, 0)
+		if s.OnReconnect != nil {
+			s.OnReconnect(err, attempt, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// SubscribeEvents is like Subscribe, but sends the channel events parsed by ParseEvent to handler.
+// Messages which are not channel events, such as responses, are skipped.
+func (s *EventStream) SubscribeEvents(ctx context.Context, channelId string, handler chan<- *Event) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages := make(chan string)
+	result := make(chan error, 1)
+	go func() {
+		result <- s.Subscribe(ctx, channelId, messages)
+	}()
+
+	for {
+		select {
+		case err := <-result:
+			return err
+		case message := <-messages:
+			receivedAt := time.Now()
+			event, err := ParseEvent([]byte(message))
+			if err != nil {
+				slog.Warn("Skipping malformed message", "err", err)
+				continue
+			}
+			if event == nil {
+				continue
+			}
+			if event.ChannelId == "" {
+				event.ChannelId = channelId
+			}
+			event.ReceivedAt = receivedAt
+
+			select {
+			case handler <- event:
+			case <-ctx.Done():
+				return <-result
+			}
+		}
+	}
+}
+
+// SubscribeChannels subscribes to the events of the channels over a connection for each channel,
+// and sends the events of all channels to handler. Event.ChannelId tells the channel of each event.
+//
+// The events are queued, so that a slow handler does not block reading the connections.
+// When more than 4096 events are queued, the oldest ones are dropped with a warning.
+//
+// It returns nil when ctx is done. When the subscription of any channel fails as Subscribe does,
+// the other subscriptions are stopped and the error is returned.
+func (s *EventStream) SubscribeChannels(ctx context.Context, channelIds []string, handler chan<- *Event) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queue := newEventQueue(eventQueueSize)
+	results := make(chan error, len(channelIds))
+	for _, channelId := range channelIds {
+		stream := *s
+		if s.OnReconnect != nil {
+			stream.OnReconnect = func(err error, attempt int, wait time.Duration) {
+				s.OnReconnect(fmt.Errorf("channel %s: %w", channelId, err), attempt, wait)
+			}
+		}
+
+		events := make(chan *Event)
+		go func() {
+			for event := range events {
+				queue.push(event)
+			}
+		}()
+		go func() {
+			err := stream.SubscribeEvents(ctx, channelId, events)
+			close(events)
+			if err != nil {
+				err = fmt.Errorf("channel %s: %w", channelId, err)
+			}
+			results <- err
+		}()
+	}
+
+	// wait returns the first error after all subscriptions are stopped
+	running := len(channelIds)
+	var result error
+	wait := func() error {
+		cancel()
+		for ; running > 0; running-- {
+			if err := <-results; err != nil && result == nil {
+				result = err
+			}
+		}
+		return result
+	}
+
+	for {
+		for {
+			event, dropped := queue.pop()
+			if dropped > 0 {
+				slog.Warn("Dropped events because they were not handled in time", "count", dropped)
+			}
+			if event == nil {
+				break
+			}
+			select {
+			case handler <- event:
+			case <-ctx.Done():
+				return wait()
+			}
+		}
+
+		select {
+		case <-queue.ready:
+		case err := <-results:
+			running--
+			if err != nil {
+				result = err
+				return wait()
+			}
+			if running == 0 {
+				return nil
+			}
+		case <-ctx.Done():
+			return wait()
+		}
+	}
+}
+
+// eventQueue is a bounded FIFO of events which never blocks the writers.
+type eventQueue struct {
+	mu      sync.Mutex
+	events  []*Event
+	size    int
+	dropped int
+	// ready receives a value when an event is pushed
+	ready chan struct{}
+}
+
+func newEventQueue(size int) *eventQueue {
+	return &eventQueue{size: size, ready: make(chan struct{}, 1)}
+}
+
+func (q *eventQueue) push(event *Event) {
+	q.mu.Lock()
+	if len(q.events) >= q.size {
+		q.events = q.events[1:]
+		q.dropped++
+	}
+	q.events = append(q.events, event)
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns the oldest event, or nil when the queue is empty,
+// and the number of events dropped since the last call.
+func (q *eventQueue) pop() (*Event, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	dropped := q.dropped
+	q.dropped = 0
+	if len(q.events) == 0 {
+		return nil, dropped
+	}
+	event := q.events[0]
+	q.events[0] = nil
+	q.events = q.events[1:]
+	return event, dropped
+}
+
+// subscribe connects to the RTC API and reads the events until the connection is lost or ctx is done.
+// onSubscribed is called when the successful response to subscribeChannelEvents or the first event is received,
+// which proves that the subscription works.
+func (s *EventStream) subscribe(ctx context.Context, channelId string, handler chan<- string, onSubscribed func()) error {
+	token, err := s.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn, res, err := s.dialer.DialContext(ctx, s.url, http.Header{"Sec-WebSocket-Protocol": []string{token}})
+	if err != nil {
+		if res != nil {
+			return fmt.Errorf("failed to connect to RTC API: %w", &APIError{StatusCode: res.StatusCode})
+		}
+		return err
+	}
+	defer conn.Close()
+
+	requestId := uuid.New().String()
+	err = conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(subscribeChannelEventsRequest, requestId, token, s.appId, channelId)))
+	if err != nil {
+		return err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	var updateRequestId atomic.Value
+	updateRequestId.Store("")
+	// failed receives the reason when the connection is closed to reconnect
+	failed := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+
+		var refresh <-chan time.Time
+		expiry := tokenExpiry(token)
+		if !expiry.IsZero() {
+			timer := time.NewTimer(refreshDelay(expiry))
+			defer timer.Stop()
+			refresh = timer.C
+		}
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				// ref: https://github.com/gorilla/websocket/issues/448
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(closeWait))
+				conn.Close()
+				return
+			case <-ticker.C:
+				conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(pingInterval))
+			case <-refresh:
+				newToken, err := s.tokens.Token(ctx)
+				if err == nil && newToken == token && time.Until(expiry) > tokenRetryInterval {
+					refresh = time.After(tokenRetryInterval)
+					continue
+				}
+				if err == nil && newToken == token {
+					err = errors.New("token source did not provide a new token")
+				}
+				if err == nil {
+					requestId := uuid.New().String()
+					updateRequestId.Store(requestId)
+					err = conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(updateAuthTokenRequest, requestId, newToken, s.appId)))
+				}
+				if err != nil {
+					failed <- fmt.Errorf("failed to update auth token: %w", err)
+					conn.Close()
+					return
+				}
+				token = newToken
+				expiry = tokenExpiry(token)
+				refresh = nil
+				if !expiry.IsZero() {
+					refresh = time.After(refreshDelay(expiry))
+				}
+			}
+		}
+	}()
+
+	subscribed := false
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case cause := <-failed:
+				return cause
+			default:
+				return err
+			}
+		}
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		if id, rpcError := parseResponse(message); id != "" {
+			if id == requestId && rpcError != nil {
+				rpcError.Method = "subscribeChannelEvents"
+				return rpcError
+			}
+			if id == requestId && !subscribed {
+				// the subscription works even if no event is notified before the connection is lost
+				subscribed = true
+				onSubscribed()
+			}
+			if id == updateRequestId.Load().(string) {
+				if rpcError != nil {
+					// the connection is closed to reconnect with a new token
+					rpcError.Method = "updateAuthToken"
+					return fmt.Errorf("failed to update auth token: %w", rpcError)
+				}
+				continue
+			}
+		}
+		if !subscribed {
+			subscribed = true
+			onSubscribed()
+		}
+
+		select {
+		case handler <- string(message):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// refreshDelay returns how long to wait before updating a token which expires at expiry.
+func refreshDelay(expiry time.Time) time.Duration {
+	remaining := time.Until(expiry)
+	if remaining < 2*tokenRefreshBefore {
+		return remaining / 2
+	}
+	return remaining - tokenRefreshBefore
+}
+
+// parseResponse returns the id and the error of message when it is a JSON-RPC response.
+// The id is empty when message is a notification.
+func parseResponse(message []byte) (string, *RPCError) {
+	var response struct {
+		Id    string `json:"id"`
+		Error *struct {
+			Code    int         `json:"code"`
+			Message string      `json:"message"`
+			Data    interface{} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(message, &response); err != nil || response.Error == nil {
+		return response.Id, nil
+	}
+	return response.Id, &RPCError{Code: response.Error.Code, Message: response.Error.Message, Data: response.Error.Data}
+}
@@ -0,0 +1,374 @@
+package skyway_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+// serveEvents returns an RTC API server which calls session for each connection after reading the subscribe request.
+func serveEvents(t *testing.T, session func(conn *websocket.Conn, requestId string, connection int)) string {
+	t.Helper()
+
+	var connections int32
+	upgrader := websocket.Upgrader{Subprotocols: []string{"token"}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var request struct {
+			Id     string `json:"id"`
+			Method string `json:"method"`
+		}
+		if err := conn.ReadJSON(&request); err != nil || request.Method != "subscribeChannelEvents" {
+			t.Errorf("unexpected request. method: %s err: %v", request.Method, err)
+			return
+		}
+		session(conn, request.Id, int(atomic.AddInt32(&connections, 1)))
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestEventStream(t *testing.T) {
+	policy := skyway.WithReconnectPolicy(skyway.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	t.Run("切断された場合は再接続して購読し直す", func(t *testing.T) {
+		url := serveEvents(t, func(conn *websocket.Conn, requestId string, connection int) {
+			conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"connection":%d}`, connection)))
+			if connection == 2 {
+				// keep the second connection open until the client closes it
+				conn.ReadMessage()
+			}
+		})
+
+		stream := skyway.NewEventStream(url, "app", skyway.StaticTokenSource("token"), policy)
+		var reconnects int32
+		stream.OnReconnect = func(err error, attempt int, wait time.Duration) {
+			atomic.AddInt32(&reconnects, 1)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := make(chan string)
+		result := make(chan error, 1)
+		go func() {
+			result <- stream.Subscribe(ctx, "channel", events)
+		}()
+
+		for _, expected := range []string{`{"connection":1}`, `{"connection":2}`} {
+			select {
+			case event := <-events:
+				if event != expected {
+					t.Errorf("event: %s expected: %s", event, expected)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("event not received")
+			}
+		}
+		cancel()
+
+		if err := <-result; err != nil {
+			t.Errorf("err: %v", err)
+		}
+		if reconnects != 1 {
+			t.Errorf("reconnects: %d", reconnects)
+		}
+	})
+
+	t.Run("イベントを受け取る前に切断された場合も購読の応答があれば再接続する", func(t *testing.T) {
+		url := serveEvents(t, func(conn *websocket.Conn, requestId string, connection int) {
+			response, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": requestId, "result": map[string]interface{}{}})
+			conn.WriteMessage(websocket.TextMessage, response)
+			if connection == 2 {
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"connection":2}`))
+				conn.ReadMessage()
+			}
+		})
+
+		stream := skyway.NewEventStream(url, "app", skyway.StaticTokenSource("token"), policy)
+		var reconnects int32
+		stream.OnReconnect = func(err error, attempt int, wait time.Duration) {
+			atomic.AddInt32(&reconnects, 1)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := make(chan string)
+		result := make(chan error, 1)
+		go func() {
+			result <- stream.Subscribe(ctx, "channel", events)
+		}()
+
+	receive:
+		for {
+			select {
+			case event := <-events:
+				if event == `{"connection":2}` {
+					break receive
+				}
+			case err := <-result:
+				t.Fatalf("err: %v", err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("event not received")
+			}
+		}
+		cancel()
+
+		if err := <-result; err != nil {
+			t.Errorf("err: %v", err)
+		}
+		if reconnects != 1 {
+			t.Errorf("reconnects: %d", reconnects)
+		}
+	})
+
+	t.Run("購読が拒否された場合はエラーを返す", func(t *testing.T) {
+		url := serveEvents(t, func(conn *websocket.Conn, requestId string, connection int) {
+			response, _ := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      requestId,
+				"error":   map[string]interface{}{"code": -32602, "message": "invalid channel"},
+			})
+			conn.WriteMessage(websocket.TextMessage, response)
+			conn.ReadMessage()
+		})
+
+		stream := skyway.NewEventStream(url, "app", skyway.StaticTokenSource("token"), policy)
+		err := stream.Subscribe(context.Background(), "channel", make(chan string))
+
+		var rpcError *skyway.RPCError
+		if !errors.As(err, &rpcError) || rpcError.Method != "subscribeChannelEvents" {
+			t.Errorf("err: %v", err)
+		}
+	})
+
+	t.Run("再接続の試行回数を超えるとエラーを返す", func(t *testing.T) {
+		url := serveEvents(t, func(conn *websocket.Conn, requestId string, connection int) {
+			// only the first connection works
+			if connection == 1 {
+				conn.WriteMessage(websocket.TextMessage, []byte(`{}`))
+			}
+		})
+
+		stream := skyway.NewEventStream(url, "app", skyway.StaticTokenSource("token"), policy)
+		var reconnects int32
+		stream.OnReconnect = func(err error, attempt int, wait time.Duration) {
+			atomic.AddInt32(&reconnects, 1)
+		}
+		events := make(chan string, 1)
+		err := stream.Subscribe(context.Background(), "channel", events)
+
+		if err == nil || reconnects != 3 {
+			t.Errorf("err: %v reconnects: %d", err, reconnects)
+		}
+	})
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	t.Run("チャンネルイベント以外のメッセージは読み飛ばす", func(t *testing.T) {
+		url := serveEvents(t, func(conn *websocket.Conn, requestId string, connection int) {
+			conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": requestId, "result": map[string]interface{}{}})
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"channelEventNotification","params":{"event":{"type":"MemberAdded","channelId":"c1","data":{"member":{"id":"m1","name":"alice"}}}}}`))
+			conn.ReadMessage()
+		})
+
+		stream := skyway.NewEventStream(url, "app", skyway.StaticTokenSource("token"))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := make(chan *skyway.Event)
+		go stream.SubscribeEvents(ctx, "c1", events)
+
+		select {
+		case event := <-events:
+			if event.Type != skyway.EventMemberAdded || event.Member.Name != "alice" {
+				t.Errorf("event: %+v", event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not received")
+		}
+	})
+}
+
+func TestSubscribeChannels(t *testing.T) {
+	t.Run("複数のチャンネルのイベントをチャンネルIDを付けて受け取る", func(t *testing.T) {
+		url := serveEvents(t, func(conn *websocket.Conn, requestId string, connection int) {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"channelEventNotification","params":{"event":{"type":"MemberAdded","data":{"member":{"id":"m1"}}}}}`))
+			conn.ReadMessage()
+		})
+
+		stream := skyway.NewEventStream(url, "app", skyway.StaticTokenSource("token"))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := make(chan *skyway.Event)
+		result := make(chan error, 1)
+		go func() {
+			result <- stream.SubscribeChannels(ctx, []string{"c1", "c2"}, events)
+		}()
+
+		channels := map[string]bool{}
+		for len(channels) < 2 {
+			select {
+			case event := <-events:
+				channels[event.ChannelId] = true
+			case <-time.After(5 * time.Second):
+				t.Fatalf("events not received. channels: %v", channels)
+			}
+		}
+		if !channels["c1"] || !channels["c2"] {
+			t.Errorf("channels: %v", channels)
+		}
+
+		cancel()
+		if err := <-result; err != nil {
+			t.Errorf("err: %v", err)
+		}
+	})
+
+	t.Run("いずれかのチャンネルの購読が拒否された場合はエラーを返す", func(t *testing.T) {
+		url := serveEvents(t, func(conn *websocket.Conn, requestId string, connection int) {
+			if connection == 1 {
+				conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": requestId, "error": map[string]interface{}{"code": -32602, "message": "invalid channel"}})
+			}
+			conn.ReadMessage()
+		})
+
+		stream := skyway.NewEventStream(url, "app", skyway.StaticTokenSource("token"))
+		err := stream.SubscribeChannels(context.Background(), []string{"c1", "c2"}, make(chan *skyway.Event))
+
+		var rpcError *skyway.RPCError
+		if !errors.As(err, &rpcError) || !strings.HasPrefix(err.Error(), "channel c") {
+			t.Errorf("err: %v", err)
+		}
+	})
+}
+
+// shortLivedTokens returns a token source which signs a new token expiring after lifetime on each call.
+func shortLivedTokens(lifetime time.Duration) skyway.TokenSource {
+	return skyway.TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"jti": uuid.New().String(),
+			"exp": time.Now().Add(lifetime).Unix(),
+		}).SignedString([]byte("secret"))
+	})
+}
+
+func TestEventStreamTokenRefresh(t *testing.T) {
+	policy := skyway.WithReconnectPolicy(skyway.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	t.Run("有効期限の前にupdateAuthTokenでトークンを更新する", func(t *testing.T) {
+		updated := make(chan string, 1)
+		url := serveEvents(t, func(conn *websocket.Conn, requestId string, connection int) {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{}`))
+
+			var request struct {
+				Id     string `json:"id"`
+				Method string `json:"method"`
+				Params struct {
+					AuthToken string `json:"authToken"`
+				} `json:"params"`
+			}
+			if err := conn.ReadJSON(&request); err != nil || request.Method != "updateAuthToken" {
+				t.Errorf("unexpected request. method: %s err: %v", request.Method, err)
+				return
+			}
+			conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": request.Id, "result": map[string]interface{}{}})
+			updated <- request.Params.AuthToken
+			conn.ReadMessage()
+		})
+
+		stream := skyway.NewEventStream(url, "app", shortLivedTokens(2*time.Second), policy)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := make(chan string, 10)
+		go stream.Subscribe(ctx, "channel", events)
+
+		select {
+		case token := <-updated:
+			if token == "" {
+				t.Error("token is empty")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("token not updated")
+		}
+	})
+
+	t.Run("更新に失敗した場合は新しいトークンで再接続する", func(t *testing.T) {
+		url := serveEvents(t, func(conn *websocket.Conn, requestId string, connection int) {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{}`))
+
+			var request struct {
+				Id string `json:"id"`
+			}
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+			conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": request.Id, "error": map[string]interface{}{"code": -32000, "message": "failed"}})
+			conn.ReadMessage()
+		})
+
+		stream := skyway.NewEventStream(url, "app", shortLivedTokens(2*time.Second), policy)
+		reconnected := make(chan error, 1)
+		stream.OnReconnect = func(err error, attempt int, wait time.Duration) {
+			select {
+			case reconnected <- err:
+			default:
+			}
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := make(chan string, 10)
+		go stream.Subscribe(ctx, "channel", events)
+
+		select {
+		case err := <-reconnected:
+			var rpcError *skyway.RPCError
+			if !errors.As(err, &rpcError) || rpcError.Method != "updateAuthToken" {
+				t.Errorf("err: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("not reconnected")
+		}
+	})
+}
+
+func TestReuseTokenSource(t *testing.T) {
+	var calls int32
+	source := skyway.TokenSourceFunc(func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return shortLivedTokens(time.Hour).Token(ctx)
+	})
+
+	t.Run("有効期限まで余裕がある場合は同じトークンを返す", func(t *testing.T) {
+		tokens := skyway.ReuseTokenSource(source, time.Minute)
+		first, _ := tokens.Token(context.Background())
+		second, _ := tokens.Token(context.Background())
+		if first != second || calls != 1 {
+			t.Errorf("calls: %d", calls)
+		}
+	})
+
+	t.Run("有効期限が近い場合は新しいトークンを返す", func(t *testing.T) {
+		tokens := skyway.ReuseTokenSource(source, 2*time.Hour)
+		first, _ := tokens.Token(context.Background())
+		second, _ := tokens.Token(context.Background())
+		if first == second {
+			t.Error("token is reused")
+		}
+	})
+}
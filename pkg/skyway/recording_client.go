@@ -1,12 +1,12 @@
-package internal
+package skyway
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 )
 
 type CommonErrorResponse struct {
@@ -103,34 +103,70 @@ type DeleteRecordingSessionResponse struct {
 	GetRecordingSessionResponse
 }
 
-func LoadRecordingOutputServiceConfig(config map[string]interface{}) (RecordingOutputService, error) {
-	var outputService RecordingOutputService
+// RecordingClient is a client of the SkyWay Recording API.
+// The token source should provide a SkyWay Admin Auth Token.
+type RecordingClient struct {
+	url        string
+	tokens     TokenSource
+	httpClient *http.Client
+	userAgent  string
+}
 
-	if v, ok := config["bucket"]; ok {
-		outputService.Bucket = v.(string)
-	}
-	if v, ok := config["access_key_id"]; ok {
-		outputService.AccessKeyId = v.(string)
+func NewRecordingClient(url string, tokens TokenSource, opts ...Option) *RecordingClient {
+	options := newClientOptions(opts)
+	return &RecordingClient{
+		url:        url,
+		tokens:     tokens,
+		httpClient: retryingHTTPClient(options.httpClient, options.retryPolicy),
+		userAgent:  options.userAgent,
 	}
-	if v, ok := config["secret_access_key"]; ok {
-		outputService.SecretAccessKey = v.(string)
-	}
-	if v, ok := config["region"]; ok {
-		outputService.Region = v.(string)
+}
+
+// do sends the request and decodes the response body into response.
+// When the status code is not expectedStatus, *APIError is returned.
+func (c *RecordingClient) do(ctx context.Context, method string, path string, request interface{}, expectedStatus int, response interface{}, problem *CommonErrorResponse) error {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return err
 	}
-	if v, ok := config["credential_file"]; ok {
-		data, err := os.ReadFile(v.(string))
+
+	var bodyReader io.Reader
+	if request != nil {
+		requestBody, err := json.Marshal(request)
 		if err != nil {
-			return outputService, err
+			return err
 		}
+		bodyReader = bytes.NewReader(requestBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bodyReader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	if request != nil || method == http.MethodGet {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
 
-		outputService.Credential = string(data)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
 	}
 
-	return outputService, nil
+	err = json.Unmarshal(body, response)
+	if res.StatusCode != expectedStatus {
+		return &APIError{StatusCode: res.StatusCode, CommonErrorResponse: *problem}
+	}
+	return err
 }
 
-func CreateSession(channelId string, publicationId string, contentType string, output RecordingOutputService, token string, url string) (CreateRecordingSessionResponse, error) {
+func (c *RecordingClient) CreateSession(ctx context.Context, channelId string, publicationId string, contentType string, output RecordingOutputService) (CreateRecordingSessionResponse, error) {
 	var response CreateRecordingSessionResponse
 
 	var request CreateRecordingSessionParams
@@ -153,96 +189,34 @@ func CreateSession(channelId string, publicationId string, contentType string, o
 	request.Output.Region = output.Region
 	request.Output.Credential = output.Credential
 
-	requestBody, err := json.Marshal(request)
-	if err != nil {
-		return response, err
-	}
-
-	bodyReader := bytes.NewReader(requestBody)
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/channels/%s/sessions", url, channelId), bodyReader)
-	if err != nil {
-		return response, err
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", userAgent)
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return response, err
-	}
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return response, err
-	}
-
-	err = json.Unmarshal(body, &response)
+	// a retried request could start a second recording session
+	err := c.do(withNonIdempotent(ctx), http.MethodPost, fmt.Sprintf("/channels/%s/sessions", channelId), request, http.StatusCreated, &response, &response.CommonErrorResponse)
 	if err != nil {
-		return response, err
-	}
-
-	if res.StatusCode != 201 {
-		return response, fmt.Errorf("failed to create recording session status: %d body: %v", res.StatusCode, response)
+		return response, fmt.Errorf("failed to create recording session: %w", err)
 	}
 
 	// TODO: 生のrequestとresponseも返して、デバッグログで表示させたい
 	return response, nil
 }
 
-func GetSession(channelId string, sessionId string, token string, url string) (GetRecordingSessionResponse, error) {
+func (c *RecordingClient) GetSession(ctx context.Context, channelId string, sessionId string) (GetRecordingSessionResponse, error) {
 	var response GetRecordingSessionResponse
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/channels/%s/sessions/%s", url, channelId, sessionId), nil)
-	if err != nil {
-		return response, err
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", userAgent)
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return response, err
-	}
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return response, err
-	}
 
-	err = json.Unmarshal(body, &response)
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/channels/%s/sessions/%s", channelId, sessionId), nil, http.StatusOK, &response, &response.CommonErrorResponse)
 	if err != nil {
-		return response, err
-	}
-
-	if res.StatusCode != 200 {
-		return response, fmt.Errorf("failed to get recording session status: %d body: %v", res.StatusCode, response)
+		return response, fmt.Errorf("failed to get recording session: %w", err)
 	}
 
 	// TODO: 生のresponseも返して、デバッグログで表示させたい
 	return response, nil
 }
 
-func DeleteSession(channelId string, sessionId string, token string, url string) (DeleteRecordingSessionResponse, error) {
+func (c *RecordingClient) DeleteSession(ctx context.Context, channelId string, sessionId string) (DeleteRecordingSessionResponse, error) {
 	var response DeleteRecordingSessionResponse
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/channels/%s/sessions/%s", url, channelId, sessionId), nil)
-	if err != nil {
-		return response, err
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-	req.Header.Set("User-Agent", userAgent)
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return response, err
-	}
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return response, err
-	}
 
-	err = json.Unmarshal(body, &response)
+	err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/channels/%s/sessions/%s", channelId, sessionId), nil, http.StatusOK, &response, &response.CommonErrorResponse)
 	if err != nil {
-		return response, err
-	}
-
-	if res.StatusCode != 200 {
-		return response, fmt.Errorf("failed to delete recording session status: %d body: %v", res.StatusCode, response)
+		return response, fmt.Errorf("failed to delete recording session: %w", err)
 	}
 
 	// TODO: 生のresponseも返して、デバッグログで表示させたい
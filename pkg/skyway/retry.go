@@ -0,0 +1,170 @@
+package skyway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how requests which failed with a transient error are retried.
+//
+// Network errors, 429 Too Many Requests and 500, 502, 503 and 504 responses are retried.
+// Requests which create a resource, such as createChannel or CreateSession, are not idempotent,
+// so they are only retried when the server surely did not process them: on 429 and when the connection could not be established.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one. Requests are not retried when it is 1 or less.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. The wait doubles on each retry up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Jitter is the fraction of the wait which is randomized, between 0 and 1.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is used when no RetryPolicy is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Jitter:         0.2,
+}
+
+// backoff returns the wait before the retry following attempt.
+// retryAfter is the Retry-After of the response, which takes precedence when it is set.
+// It is limited to MaxBackoff too, so that a server cannot make the client wait longer than configured.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if p.MaxBackoff > 0 && retryAfter > p.MaxBackoff {
+			return p.MaxBackoff
+		}
+		return retryAfter
+	}
+
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		wait -= time.Duration(float64(wait) * p.Jitter * rand.Float64())
+	}
+	return wait
+}
+
+type nonIdempotentKey struct{}
+
+// withNonIdempotent marks the requests made with ctx as not idempotent.
+func withNonIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonIdempotentKey{}, true)
+}
+
+func isIdempotent(ctx context.Context) bool {
+	nonIdempotent, _ := ctx.Value(nonIdempotentKey{}).(bool)
+	return !nonIdempotent
+}
+
+// parseRetryAfter parses the Retry-After header, which is either seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// retryTransport retries requests according to its policy.
+// It is used as the transport of the HTTP client, so that both the JSON-RPC and the REST clients share it.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) retryable(ctx context.Context, res *http.Response, err error) bool {
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		if isIdempotent(ctx) {
+			return true
+		}
+		// the request was not sent when the connection could not be established
+		var opError *net.OpError
+		return errors.As(err, &opError) && opError.Op == "dial"
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(ctx)
+	}
+	return false
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		res, err := t.base.RoundTrip(req)
+		if attempt >= t.policy.MaxAttempts || !t.retryable(ctx, res, err) {
+			return res, err
+		}
+		// the body has been consumed and must be rewound
+		if req.Body != nil && req.GetBody == nil {
+			return res, err
+		}
+
+		var retryAfter time.Duration
+		if res != nil {
+			retryAfter = parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		wait := t.policy.backoff(attempt, retryAfter)
+		slog.Debug("Retrying request", "url", req.URL.Redacted(), "attempt", attempt, "wait", wait, "err", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// retryingHTTPClient returns a copy of client which retries requests according to policy.
+func retryingHTTPClient(client *http.Client, policy RetryPolicy) *http.Client {
+	if policy.MaxAttempts <= 1 {
+		return client
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	retrying := *client
+	retrying.Transport = &retryTransport{base: base, policy: policy}
+	return &retrying
+}
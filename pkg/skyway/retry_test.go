@@ -0,0 +1,109 @@
+package skyway_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+var testRetryPolicy = skyway.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     10 * time.Millisecond,
+}
+
+// serveStatuses returns a server which responds with statuses in order, and then with the findChannel fixture.
+func serveStatuses(t *testing.T, attempts *int32, statuses ...int) *httptest.Server {
+	t.Helper()
+
+	fixture, err := os.ReadFile(filepath.Join("testdata", "channel_api", "findChannel.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(atomic.AddInt32(attempts, 1))
+		if attempt <= len(statuses) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(statuses[attempt-1])
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("5xxと429はリトライする", func(t *testing.T) {
+		var attempts int32
+		server := serveStatuses(t, &attempts, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"), skyway.WithRetryPolicy(testRetryPolicy))
+		channel, err := client.FindChannel(context.Background(), "5f4c7c3e-6a8e-4b4f-9a5e-2a1f0f3b7c11", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if channel.Id == "" || attempts != 3 {
+			t.Errorf("channel: %s attempts: %d", channel.Id, attempts)
+		}
+	})
+
+	t.Run("最大試行回数を超えるとエラーになる", func(t *testing.T) {
+		var attempts int32
+		server := serveStatuses(t, &attempts, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"), skyway.WithRetryPolicy(testRetryPolicy))
+		_, err := client.FindChannel(context.Background(), "5f4c7c3e-6a8e-4b4f-9a5e-2a1f0f3b7c11", "")
+		if err == nil || attempts != 3 {
+			t.Errorf("err: %v attempts: %d", err, attempts)
+		}
+	})
+
+	t.Run("冪等でないメソッドは5xxでリトライしない", func(t *testing.T) {
+		var attempts int32
+		server := serveStatuses(t, &attempts, http.StatusInternalServerError)
+
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"), skyway.WithRetryPolicy(testRetryPolicy))
+		_, err := client.CreateChannel(context.Background(), "room", "")
+		if err == nil || attempts != 1 {
+			t.Errorf("err: %v attempts: %d", err, attempts)
+		}
+	})
+
+	t.Run("冪等でないメソッドも429ではリトライする", func(t *testing.T) {
+		var attempts int32
+		server := serveStatuses(t, &attempts, http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests)
+
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"), skyway.WithRetryPolicy(testRetryPolicy))
+		_, err := client.CreateChannel(context.Background(), "room", "")
+		if err == nil || attempts != 3 {
+			t.Errorf("err: %v attempts: %d", err, attempts)
+		}
+	})
+	t.Run("Retry-AfterがMaxBackoffより長い場合はMaxBackoffだけ待つ", func(t *testing.T) {
+		var attempts int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		t.Cleanup(server.Close)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		client := skyway.NewChannelClient(server.URL, skyway.StaticTokenSource("token"), skyway.WithRetryPolicy(testRetryPolicy))
+		_, err := client.FindChannel(ctx, "5f4c7c3e-6a8e-4b4f-9a5e-2a1f0f3b7c11", "")
+		if err == nil || ctx.Err() != nil || attempts != 3 {
+			t.Errorf("err: %v attempts: %d", err, attempts)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package skyway
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	// WebhookSignatureHeader has the HMAC-SHA256 signature of the request body, such as "sha256=<hex>".
+	WebhookSignatureHeader = "X-Skyway-Cli-Signature-256"
+	// WebhookDeliveryHeader has the unique id of the delivery, which is the same on retries.
+	WebhookDeliveryHeader = "X-Skyway-Cli-Delivery"
+
+	// webhookTimeout is how long a delivery may take including its retries.
+	webhookTimeout = 30 * time.Second
+)
+
+// SignWebhookPayload returns the value of WebhookSignatureHeader for body signed with secret.
+// Receivers verify the request by comparing the header with it, using hmac.Equal.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type webhookDelivery struct {
+	id   string
+	body []byte
+}
+
+// Webhook posts channel events as JSON to an HTTP endpoint in the order they are sent.
+//
+// The body is the channelEventNotification message as received from the RTC API (Event.Raw),
+// with the top-level "channelId" of the event added, which is the same as the JSON output of "channel watch".
+// The event is at params.event, such as {"jsonrpc":"2.0","method":"channelEventNotification","params":{"event":{"type":"MemberAdded",...}},"channelId":"..."}.
+//
+// The events are queued and delivered in the background, so that a slow endpoint does not block receiving the events.
+// When the queue is full, the events are dropped.
+// Failed deliveries are retried according to the retry policy, with the same WebhookDeliveryHeader.
+type Webhook struct {
+	url       string
+	secret    string
+	client    *http.Client
+	userAgent string
+
+	queue  chan webhookDelivery
+	done   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// OnError is called when a delivery fails after the retries. It may be nil, and must be set before Send is called.
+	OnError func(err error)
+}
+
+// NewWebhook returns a webhook which queues up to queueSize events, and starts delivering them.
+// Close must be called to stop it.
+func NewWebhook(url string, secret string, queueSize int, opts ...Option) *Webhook {
+	options := newClientOptions(opts)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Webhook{
+		url:       url,
+		secret:    secret,
+		client:    retryingHTTPClient(options.httpClient, options.retryPolicy),
+		userAgent: options.userAgent,
+		queue:     make(chan webhookDelivery, queueSize),
+		done:      make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	go w.run()
+	return w
+}
+
+// Send queues the event, and reports whether it was queued.
+// It returns false without blocking when the queue is full.
+// The event must have Raw, such as the events received by EventStream. It must not be called after Close.
+func (w *Webhook) Send(event *Event) (bool, error) {
+	if len(event.Raw) == 0 {
+		return false, fmt.Errorf("failed to send webhook. event %s has no raw notification", event.Type)
+	}
+	body, err := sjson.SetBytes(event.Raw, "channelId", event.ChannelId)
+	if err != nil {
+		return false, err
+	}
+
+	select {
+	case w.queue <- webhookDelivery{id: uuid.New().String(), body: body}:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// Close stops accepting events, and waits until the queued events are delivered or ctx is done.
+// The deliveries in progress are cancelled when ctx is done.
+func (w *Webhook) Close(ctx context.Context) error {
+	close(w.queue)
+	select {
+	case <-w.done:
+		w.cancel()
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		<-w.done
+		return ctx.Err()
+	}
+}
+
+func (w *Webhook) run() {
+	defer close(w.done)
+	for delivery := range w.queue {
+		if w.ctx.Err() != nil {
+			continue
+		}
+		if err := w.deliver(w.ctx, delivery); err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+	}
+}
+
+func (w *Webhook) deliver(ctx context.Context, delivery webhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(delivery.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", w.userAgent)
+	req.Header.Set(WebhookDeliveryHeader, delivery.id)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(w.secret, delivery.body))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook. delivery: %s err: %w", delivery.id, err)
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("failed to deliver webhook. delivery: %s err: %w", delivery.id, &APIError{StatusCode: res.StatusCode})
+	}
+	return nil
+}
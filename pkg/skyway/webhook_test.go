@@ -0,0 +1,111 @@
+package skyway_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kadoshita/skyway-cli/pkg/skyway"
+)
+
+func TestWebhook(t *testing.T) {
+	event, err := skyway.ParseEvent([]byte(`{"jsonrpc":"2.0","method":"channelEventNotification","params":{"event":{"type":"MemberAdded","data":{"member":{"id":"m1","name":"alice"}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.ChannelId = "c1"
+
+	t.Run("署名付きでイベントをPOSTし、失敗した場合は同じ配信IDでリトライする", func(t *testing.T) {
+		var mu sync.Mutex
+		var deliveries []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get(skyway.WebhookSignatureHeader) != skyway.SignWebhookPayload("secret", body) {
+				t.Errorf("invalid signature: %s", r.Header.Get(skyway.WebhookSignatureHeader))
+			}
+			// the body is the notification tagged with the channel
+			received, err := skyway.ParseEvent(body)
+			var tag struct {
+				ChannelId string `json:"channelId"`
+			}
+			json.Unmarshal(body, &tag)
+			if err != nil || received == nil || received.Type != skyway.EventMemberAdded || received.Member.Name != "alice" || tag.ChannelId != "c1" {
+				t.Errorf("body: %s", body)
+			}
+
+			mu.Lock()
+			deliveries = append(deliveries, r.Header.Get(skyway.WebhookDeliveryHeader))
+			attempt := len(deliveries)
+			mu.Unlock()
+			if attempt == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		webhook := skyway.NewWebhook(server.URL, "secret", 10, skyway.WithRetryPolicy(testRetryPolicy))
+		webhook.OnError = func(err error) {
+			t.Errorf("err: %v", err)
+		}
+		if queued, err := webhook.Send(event); !queued || err != nil {
+			t.Fatalf("queued: %v err: %v", queued, err)
+		}
+		if err := webhook.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(deliveries) != 2 || deliveries[0] == "" || deliveries[0] != deliveries[1] {
+			t.Errorf("deliveries: %v", deliveries)
+		}
+	})
+
+	t.Run("キューが一杯の場合はイベントを捨てる", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		t.Cleanup(server.Close)
+
+		webhook := skyway.NewWebhook(server.URL, "secret", 1)
+		dropped := false
+		for i := 0; i < 3; i++ {
+			if queued, _ := webhook.Send(event); !queued {
+				dropped = true
+			}
+		}
+		close(release)
+		webhook.Close(context.Background())
+
+		if !dropped {
+			t.Error("no event was dropped")
+		}
+	})
+
+	t.Run("リトライしても失敗した場合はOnErrorを呼ぶ", func(t *testing.T) {
+		server := serveResponse(t, http.StatusBadRequest, ``)
+
+		webhook := skyway.NewWebhook(server.URL, "secret", 10, skyway.WithRetryPolicy(testRetryPolicy))
+		failed := make(chan error, 1)
+		webhook.OnError = func(err error) {
+			failed <- err
+		}
+		webhook.Send(event)
+
+		select {
+		case err := <-failed:
+			var apiError *skyway.APIError
+			if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusBadRequest {
+				t.Errorf("err: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("OnError not called")
+		}
+		webhook.Close(context.Background())
+	})
+}